@@ -9,7 +9,7 @@ import (
 	"github.com/c-bata/go-prompt/completer"
 )
 
-var filePathCompleter = completer.FilePathCompleter{
+var filePathCompleter = &completer.FilePathCompleter{
 	IgnoreCase: true,
 	Filter: func(fi os.FileInfo) bool {
 		return fi.IsDir() || strings.HasSuffix(fi.Name(), ".go")
@@ -24,9 +24,9 @@ func completerFunc(d prompt.Document) []prompt.Suggest {
 	t := d.GetWordBeforeCursor()
 	if strings.HasPrefix(t, "--") {
 		return []prompt.Suggest{
-			{"--foo", ""},
-			{"--bar", ""},
-			{"--baz", ""},
+			{Text: "--foo"},
+			{Text: "--bar"},
+			{Text: "--baz"},
 		}
 	}
 	return filePathCompleter.Complete(d)
@@ -37,7 +37,6 @@ func main() {
 		executor,
 		completerFunc,
 		prompt.OptionPrefix(">>> "),
-		prompt.OptionCompletionWordSeparator(completer.FilePathCompletionSeparator),
 	)
 	p.Run()
 }
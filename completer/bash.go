@@ -0,0 +1,165 @@
+package completer
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// bashHelper calls the completion function registered by `complete -F` for the command,
+// and prints COMPREPLY line by line followed by the marker.
+const bashHelper = `__go_prompt_complete() {
+	local spec fn
+	spec=$(complete -p -- "$1" 2>/dev/null)
+	if [ -z "$spec" ] && declare -F _completion_loader >/dev/null; then
+		_completion_loader "$1" >/dev/null 2>&1
+		spec=$(complete -p -- "$1" 2>/dev/null)
+	fi
+	COMPREPLY=()
+	case "$spec" in
+	*" -F "*)
+		fn=${spec##* -F }
+		fn=${fn%% *}
+		"$fn" "$1" "${COMP_WORDS[COMP_CWORD]}" "${COMP_WORDS[COMP_CWORD-1]}" </dev/null >/dev/null 2>&1
+		;;
+	esac
+	if [ ${#COMPREPLY[@]} -gt 0 ]; then
+		printf '%s\n' "${COMPREPLY[@]}"
+	fi
+	printf '%s\n' "$2"
+}
+`
+
+// BashCompleter is a completer bridging to bash programmable completion.
+// It runs bash sourcing Scripts, calls the function registered by `complete -F` with
+// COMP_WORDS, COMP_CWORD, COMP_LINE and COMP_POINT built from the document, and returns COMPREPLY.
+// The bash process is reused across keystrokes and restarted if it exits or times out.
+// Call Close to stop it.
+type BashCompleter struct {
+	// Scripts are sourced in order when bash starts. For example,
+	// "/usr/share/bash-completion/bash_completion" loads completions of installed commands on demand,
+	// and "/usr/share/bash-completion/completions/git" registers the completion of git.
+	Scripts []string
+	// Bash is the path of bash. "bash" is used if empty.
+	Bash string
+	// Timeout is how long it waits for the completion function. It is 2 seconds if 0.
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	proc *lineProcess
+	seq  int
+}
+
+// Complete returns COMPREPLY of the bash completion function for the command.
+func (c *BashCompleter) Complete(d prompt.Document) []prompt.Suggest {
+	words := d.ShellArgs()
+	cword := d.ShellArgIndex()
+	if cword == len(words) {
+		words = append(words, "")
+	}
+	if cword == 0 {
+		return nil
+	}
+
+	replies, err := c.complete(words, cword, d.Text, len(d.TextBeforeCursor()))
+	if err != nil {
+		debug.Log("completer: cannot complete by bash:" + err.Error())
+		return nil
+	}
+
+	// Replace the whole argument under the cursor since bash completes arguments rather than words.
+	_, quote := d.ShellWordBeforeCursor()
+	r := &prompt.SuggestRange{Start: -len([]rune(d.TextBeforeCursor()[d.FindStartOfPreviousShellWord():]))}
+	suggests := make([]prompt.Suggest, 0, len(replies))
+	for _, reply := range replies {
+		text := prompt.QuoteShellWord(reply, quote)
+		suggests = append(suggests, prompt.Suggest{Text: text, DisplayText: reply, Range: r})
+	}
+	return suggests
+}
+
+func (c *BashCompleter) complete(words []string, cword int, line string, point int) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.proc == nil {
+		proc, err := c.start()
+		if err != nil {
+			return nil, err
+		}
+		c.proc = proc
+	}
+
+	c.seq++
+	marker := fmt.Sprintf("__go_prompt_done_%d", c.seq)
+	quoted := make([]string, len(words))
+	for i := range words {
+		quoted[i] = prompt.QuoteShellWord(words[i], prompt.ShellQuoteSingle)
+	}
+	script := fmt.Sprintf("COMP_WORDS=(%s); COMP_CWORD=%d; COMP_LINE=%s; COMP_POINT=%d; COMP_TYPE=9; COMP_KEY=9; __go_prompt_complete %s %s\n",
+		strings.Join(quoted, " "), cword, prompt.QuoteShellWord(line, prompt.ShellQuoteSingle), point, quoted[0], marker)
+	if _, err := io.WriteString(c.proc.stdin, script); err != nil {
+		c.stop()
+		return nil, err
+	}
+
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 2 * time.Second
+	}
+	deadline := time.After(timeout)
+	var replies []string
+	for {
+		l, err := c.proc.readLine(deadline)
+		if err != nil {
+			c.stop()
+			return nil, err
+		}
+		if l == marker {
+			return replies, nil
+		}
+		replies = append(replies, l)
+	}
+}
+
+func (c *BashCompleter) start() (*lineProcess, error) {
+	bash := c.Bash
+	if bash == "" {
+		bash = "bash"
+	}
+	p, err := startLineProcess(exec.Command(bash, "--noprofile", "--norc"))
+	if err != nil {
+		return nil, err
+	}
+
+	var b strings.Builder
+	b.WriteString(bashHelper)
+	for _, s := range c.Scripts {
+		b.WriteString("source " + prompt.QuoteShellWord(s, prompt.ShellQuoteSingle) + " >/dev/null 2>&1\n")
+	}
+	if _, err := io.WriteString(p.stdin, b.String()); err != nil {
+		p.kill()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (c *BashCompleter) stop() {
+	if c.proc != nil {
+		c.proc.kill()
+		c.proc = nil
+	}
+}
+
+// Close stops the bash process. It is started again by the next completion.
+func (c *BashCompleter) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stop()
+	return nil
+}
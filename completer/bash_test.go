@@ -0,0 +1,105 @@
+package completer
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func TestBashCompleter(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+	dir, err := ioutil.TempDir("", "go-prompt-bash")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	script := `_mycli() {
+	case "$3" in
+	mycli) COMPREPLY=( $(compgen -W "start stop status" -- "$2") ) ;;
+	--name) COMPREPLY=( "my service" ) ;;
+	*) COMPREPLY=( "cword=$COMP_CWORD" "words=${#COMP_WORDS[@]}" "point=$COMP_POINT" ) ;;
+	esac
+}
+complete -F _mycli mycli
+_count() { n=$((n+1)); COMPREPLY=( $n ); }
+complete -F _count count
+_slow() { sleep 5; }
+complete -F _slow slow
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "mycli.bash"), []byte(script), 0644); err != nil {
+		t.Fatal(err)
+	}
+	c := &BashCompleter{Scripts: []string{filepath.Join(dir, "mycli.bash")}, Timeout: 200 * time.Millisecond}
+	defer c.Close()
+
+	scenarioTable := []struct {
+		text     string
+		expected []prompt.Suggest
+	}{
+		{
+			text: "mycli st",
+			expected: []prompt.Suggest{
+				{Text: "start", DisplayText: "start", Range: &prompt.SuggestRange{Start: -2}},
+				{Text: "stop", DisplayText: "stop", Range: &prompt.SuggestRange{Start: -2}},
+				{Text: "status", DisplayText: "status", Range: &prompt.SuggestRange{Start: -2}},
+			},
+		},
+		{
+			text: "mycli --name my",
+			expected: []prompt.Suggest{
+				{Text: `my\ service`, DisplayText: "my service", Range: &prompt.SuggestRange{Start: -2}},
+			},
+		},
+		{
+			text: `mycli --name "my`,
+			expected: []prompt.Suggest{
+				{Text: `"my service"`, DisplayText: "my service", Range: &prompt.SuggestRange{Start: -3}},
+			},
+		},
+		{
+			text: "mycli start ",
+			expected: []prompt.Suggest{
+				{Text: "cword=2", DisplayText: "cword=2", Range: &prompt.SuggestRange{}},
+				{Text: "words=3", DisplayText: "words=3", Range: &prompt.SuggestRange{}},
+				{Text: "point=12", DisplayText: "point=12", Range: &prompt.SuggestRange{}},
+			},
+		},
+		{
+			text:     "unknown ",
+			expected: []prompt.Suggest{},
+		},
+		{
+			text:     "mycl",
+			expected: nil,
+		},
+	}
+	for _, s := range scenarioTable {
+		if actual := c.Complete(newDocument(s.text)); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("%q: Should be %#v, but got %#v", s.text, s.expected, actual)
+		}
+	}
+
+	// The process is reused, so the counter in bash is incremented.
+	for _, expected := range []string{"1", "2"} {
+		if actual := suggestTexts(c.Complete(newDocument("count "))); !reflect.DeepEqual(actual, []string{expected}) {
+			t.Errorf("Should be %#v, but got %#v", []string{expected}, actual)
+		}
+	}
+
+	// The process is restarted after a timeout.
+	if actual := c.Complete(newDocument("slow ")); actual != nil {
+		t.Errorf("Should be nil after timeout, but got %#v", actual)
+	}
+	if actual := suggestTexts(c.Complete(newDocument("count "))); !reflect.DeepEqual(actual, []string{"1"}) {
+		t.Errorf("Should be %#v, but got %#v", []string{"1"}, actual)
+	}
+}
@@ -0,0 +1,180 @@
+package completer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func newDocument(text string) prompt.Document {
+	buf := prompt.NewBuffer()
+	buf.InsertText(text, false, true)
+	return *buf.Document()
+}
+
+func suggestTexts(suggests []prompt.Suggest) []string {
+	texts := make([]string, 0, len(suggests))
+	for _, s := range suggests {
+		texts = append(texts, s.Text)
+	}
+	return texts
+}
+
+func TestExecutableCompleter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("executable bits are not supported on windows")
+	}
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+	for _, name := range []string{"mytool", "my dir/mytool", "my dir/myother"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), nil, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+filepath.Join(dir, "my dir"))
+
+	c := &ExecutableCompleter{}
+	actual := c.Complete(newDocument("my"))
+	expected := []prompt.Suggest{
+		{Text: "mytool", Description: dir},
+		{Text: "myother", Description: filepath.Join(dir, "my dir")},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+
+	// Callers cannot modify the cache even for the empty word.
+	c.Complete(newDocument(""))[0].Text = "modified"
+	if actual := c.Complete(newDocument("")); actual[0].Text == "modified" {
+		t.Errorf("The cache should not be modified, but got %#v", actual)
+	}
+
+	// The cache is invalidated when $PATH changes.
+	os.Setenv("PATH", dir)
+	if actual := suggestTexts(c.Complete(newDocument("my"))); !reflect.DeepEqual(actual, []string{"mytool"}) {
+		t.Errorf("Should be %#v, but got %#v", []string{"mytool"}, actual)
+	}
+}
+
+func TestEnvCompleter(t *testing.T) {
+	defer os.Unsetenv("GO_PROMPT_TEST_FOO")
+	defer os.Unsetenv("GO_PROMPT_TEST_BAR")
+	os.Setenv("GO_PROMPT_TEST_FOO", "foo")
+	os.Setenv("GO_PROMPT_TEST_BAR", "bar")
+
+	scenarioTable := []struct {
+		text     string
+		expected []prompt.Suggest
+	}{
+		{
+			text: "echo $GO_PROMPT_TEST_F",
+			expected: []prompt.Suggest{
+				{Text: "$GO_PROMPT_TEST_FOO", DisplayText: "GO_PROMPT_TEST_FOO", Description: "foo", Range: &prompt.SuggestRange{Start: -17}},
+			},
+		},
+		{
+			text: "cd --dir=${GO_PROMPT_TEST_",
+			expected: []prompt.Suggest{
+				{Text: "${GO_PROMPT_TEST_BAR}", DisplayText: "GO_PROMPT_TEST_BAR", Description: "bar", Range: &prompt.SuggestRange{Start: -17}},
+				{Text: "${GO_PROMPT_TEST_FOO}", DisplayText: "GO_PROMPT_TEST_FOO", Description: "foo", Range: &prompt.SuggestRange{Start: -17}},
+			},
+		},
+		{
+			text:     "echo GO_PROMPT_TEST_F",
+			expected: nil,
+		},
+		{
+			text:     `echo \$GO_PROMPT_TEST_F`,
+			expected: nil,
+		},
+		{
+			text:     "echo $GO_PROMPT_TEST_F/",
+			expected: nil,
+		},
+	}
+
+	c := &EnvCompleter{}
+	for i, s := range scenarioTable {
+		if actual := c.Complete(newDocument(s.text)); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("[%d] Should be %#v, but got %#v", i, s.expected, actual)
+		}
+	}
+}
+
+func TestSSHHostCompleter(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+	config := `# comment
+Host dev dev-*
+    HostName dev.example.com
+Host=staging
+Host *
+    User me
+`
+	knownHosts := `dev.example.com,192.168.0.1 ssh-ed25519 AAAA
+[db.example.com]:2222 ssh-ed25519 AAAA
+|1|aGFzaGVk|aGFzaGVk ssh-ed25519 AAAA
+@cert-authority *.example.com ssh-ed25519 AAAA
+`
+	c := &SSHHostCompleter{
+		ConfigPath:     filepath.Join(dir, "config"),
+		KnownHostsPath: filepath.Join(dir, "known_hosts"),
+	}
+	if err := ioutil.WriteFile(c.ConfigPath, []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(c.KnownHostsPath, []byte(knownHosts), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarioTable := []struct {
+		text     string
+		expected []prompt.Suggest
+	}{
+		{
+			text: "ssh ",
+			expected: []prompt.Suggest{
+				{Text: "dev", Description: "ssh config"},
+				{Text: "staging", Description: "ssh config"},
+				{Text: "dev.example.com", Description: "known host"},
+				{Text: "192.168.0.1", Description: "known host"},
+				{Text: "db.example.com", Description: "known host"},
+			},
+		},
+		{
+			text: "ssh me@d",
+			expected: []prompt.Suggest{
+				{Text: "dev", Description: "ssh config", Range: &prompt.SuggestRange{Start: -1}},
+				{Text: "dev.example.com", Description: "known host", Range: &prompt.SuggestRange{Start: -1}},
+				{Text: "db.example.com", Description: "known host", Range: &prompt.SuggestRange{Start: -1}},
+			},
+		},
+		{
+			text: "scp me@",
+			expected: []prompt.Suggest{
+				{Text: "dev", Description: "ssh config", Range: &prompt.SuggestRange{}},
+				{Text: "staging", Description: "ssh config", Range: &prompt.SuggestRange{}},
+				{Text: "dev.example.com", Description: "known host", Range: &prompt.SuggestRange{}},
+				{Text: "192.168.0.1", Description: "known host", Range: &prompt.SuggestRange{}},
+				{Text: "db.example.com", Description: "known host", Range: &prompt.SuggestRange{}},
+			},
+		},
+		{
+			text: "ssh st",
+			expected: []prompt.Suggest{
+				{Text: "staging", Description: "ssh config"},
+			},
+		},
+	}
+	for i, s := range scenarioTable {
+		if actual := c.Complete(newDocument(s.text)); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("[%d] Should be %#v, but got %#v", i, s.expected, actual)
+		}
+	}
+}
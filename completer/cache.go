@@ -0,0 +1,133 @@
+package completer
+
+import (
+	"container/list"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// Cache is a completer which memoizes the suggestions of a slow Completer.
+// Completer should return all candidates for the argument regardless of the word before the cursor,
+// and Cache filters them by the word before the cursor.
+// So the suggestions are computed once and subsequent keystrokes hit the cache.
+type Cache struct {
+	Completer prompt.Completer
+	// Key returns the cache key of a document. The text before the word before the cursor is used if nil.
+	// See also KeyByArgIndex.
+	Key func(d prompt.Document) string
+	// TTL is how long the suggestions are cached. They never expire if 0.
+	TTL time.Duration
+	// MaxEntries is the number of keys cached. The least recently used one is evicted if exceeded.
+	// There is no limit if 0.
+	MaxEntries int
+	// Filter filters cached suggestions by the word before the cursor. FilterHasPrefix is used if nil.
+	Filter     prompt.Filter
+	IgnoreCase bool
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	key      string
+	suggests []prompt.Suggest
+	expireAt time.Time
+}
+
+// KeyByArgIndex returns the command name and the index of the argument under the cursor
+// like "git:1", which is useful as Cache.Key for completers depending on only them.
+func KeyByArgIndex(d prompt.Document) string {
+	var name string
+	if args := d.ShellArgs(); len(args) > 0 {
+		name = args[0]
+	}
+	return name + ":" + strconv.Itoa(d.ShellArgIndex())
+}
+
+// Complete returns cached suggestions filtered by the word before the cursor.
+func (c *Cache) Complete(d prompt.Document) []prompt.Suggest {
+	filter := c.Filter
+	if filter == nil {
+		filter = prompt.FilterHasPrefix
+	}
+	return copySuggests(filter(c.get(d), d.GetWordBeforeCursor(), c.IgnoreCase))
+}
+
+func (c *Cache) get(d prompt.Document) []prompt.Suggest {
+	var key string
+	if c.Key != nil {
+		key = c.Key(d)
+	} else {
+		key = strings.TrimSuffix(d.TextBeforeCursor(), d.GetWordBeforeCursor())
+	}
+
+	c.mu.Lock()
+	c.init()
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*cacheEntry)
+		if entry.expireAt.IsZero() || c.now().Before(entry.expireAt) {
+			c.lru.MoveToFront(e)
+			c.mu.Unlock()
+			return entry.suggests
+		}
+		c.remove(e)
+	}
+	c.mu.Unlock()
+
+	// Do not hold the lock while completing so that Invalidate is not blocked by a slow completer.
+	suggests := c.Completer(d)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.init() // InvalidateAll may be called while completing.
+	entry := &cacheEntry{key: key, suggests: suggests}
+	if c.TTL > 0 {
+		entry.expireAt = c.now().Add(c.TTL)
+	}
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+	c.entries[key] = c.lru.PushFront(entry)
+	if c.MaxEntries > 0 && c.lru.Len() > c.MaxEntries {
+		c.remove(c.lru.Back())
+	}
+	return suggests
+}
+
+func (c *Cache) init() {
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.lru = list.New()
+	}
+	if c.now == nil {
+		c.now = time.Now
+	}
+}
+
+func (c *Cache) remove(e *list.Element) {
+	c.lru.Remove(e)
+	delete(c.entries, e.Value.(*cacheEntry).key)
+}
+
+// Invalidate removes the suggestions cached for key.
+func (c *Cache) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.remove(e)
+	}
+}
+
+// InvalidateAll removes all cached suggestions.
+func (c *Cache) InvalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+	c.lru = nil
+}
@@ -0,0 +1,102 @@
+package completer
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+type countingCompleter struct {
+	calls int
+}
+
+func (c *countingCompleter) Complete(d prompt.Document) []prompt.Suggest {
+	c.calls++
+	return []prompt.Suggest{{Text: "main"}, {Text: "master"}, {Text: "develop"}}
+}
+
+func TestCache(t *testing.T) {
+	counter := &countingCompleter{}
+	c := &Cache{Completer: counter.Complete}
+
+	for _, s := range []struct {
+		text     string
+		expected []string
+		calls    int
+	}{
+		{text: "git checkout ", expected: []string{"main", "master", "develop"}, calls: 1},
+		{text: "git checkout m", expected: []string{"main", "master"}, calls: 1},
+		{text: "git checkout ma", expected: []string{"main", "master"}, calls: 1},
+		{text: "git checkout mas", expected: []string{"master"}, calls: 1},
+		{text: "git merge ma", expected: []string{"main", "master"}, calls: 2},
+	} {
+		if actual := suggestTexts(c.Complete(newDocument(s.text))); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("%q: Should be %#v, but got %#v", s.text, s.expected, actual)
+		}
+		if counter.calls != s.calls {
+			t.Errorf("%q: Should be called %d times, but got %d", s.text, s.calls, counter.calls)
+		}
+	}
+
+	c.Invalidate("git checkout ")
+	c.Complete(newDocument("git checkout m"))
+	if counter.calls != 3 {
+		t.Errorf("Should be called again after invalidation, but got %d", counter.calls)
+	}
+	c.InvalidateAll()
+	c.Complete(newDocument("git merge "))
+	if counter.calls != 4 {
+		t.Errorf("Should be called again after invalidation, but got %d", counter.calls)
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Now()
+	counter := &countingCompleter{}
+	c := &Cache{
+		Completer: counter.Complete,
+		Key:       KeyByArgIndex,
+		TTL:       time.Minute,
+		now:       func() time.Time { return now },
+	}
+
+	c.Complete(newDocument("git checkout m"))
+	now = now.Add(59 * time.Second)
+	c.Complete(newDocument("git checkout ma"))
+	if counter.calls != 1 {
+		t.Errorf("Should be cached, but called %d times", counter.calls)
+	}
+	now = now.Add(time.Second)
+	c.Complete(newDocument("git checkout mas"))
+	if counter.calls != 2 {
+		t.Errorf("Should be expired, but called %d times", counter.calls)
+	}
+}
+
+func TestCacheMaxEntries(t *testing.T) {
+	counter := &countingCompleter{}
+	c := &Cache{Completer: counter.Complete, Key: KeyByArgIndex, MaxEntries: 2}
+
+	for _, text := range []string{"a ", "b ", "a ", "c ", "a ", "b "} {
+		c.Complete(newDocument(text))
+	}
+	// "b" is evicted when "c" is added since "a" is used more recently.
+	if counter.calls != 4 {
+		t.Errorf("Should be called 4 times, but got %d", counter.calls)
+	}
+}
+
+func TestKeyByArgIndex(t *testing.T) {
+	for text, expected := range map[string]string{
+		"":               ":0",
+		"gi":             "gi:0",
+		"git checkout":   "git:1",
+		"git checkout m": "git:2",
+	} {
+		if actual := KeyByArgIndex(newDocument(text)); actual != expected {
+			t.Errorf("%q: Should be %#v, but got %#v", text, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package completer
+
+import (
+	"sort"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// Merge returns a completer which concatenates the suggestions of completers in order.
+// If some suggestions have the same Text, only the first one is returned.
+func Merge(completers ...prompt.Completer) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		var ret []prompt.Suggest
+		seen := make(map[string]struct{})
+		for _, c := range completers {
+			for _, s := range c(d) {
+				if _, ok := seen[s.Text]; ok {
+					continue
+				}
+				seen[s.Text] = struct{}{}
+				ret = append(ret, s)
+			}
+		}
+		return ret
+	}
+}
+
+// Fallback returns a completer which returns the suggestions of the first completer returning any.
+func Fallback(completers ...prompt.Completer) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		for _, c := range completers {
+			if suggests := c(d); len(suggests) > 0 {
+				return suggests
+			}
+		}
+		return nil
+	}
+}
+
+// ByCommand returns a completer which dispatches to the completer of the command name,
+// that is the first argument, once the command name is typed.
+// defaultCompleter is used to complete the command name itself or unknown commands. It may be nil.
+func ByCommand(commands map[string]prompt.Completer, defaultCompleter prompt.Completer) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		if d.ShellArgIndex() > 0 {
+			if c, ok := commands[d.ShellArgs()[0]]; ok {
+				return c(d)
+			}
+		}
+		if defaultCompleter == nil {
+			return nil
+		}
+		return defaultCompleter(d)
+	}
+}
+
+// ByArgIndex returns a completer which dispatches to completers[i] for the i-th argument.
+// The command name is the 0th argument. A nil completer or an argument out of range completes nothing.
+func ByArgIndex(completers ...prompt.Completer) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		i := d.ShellArgIndex()
+		if i >= len(completers) || completers[i] == nil {
+			return nil
+		}
+		return completers[i](d)
+	}
+}
+
+// WithFilter returns a completer which filters the suggestions of c by the word before the cursor.
+func WithFilter(c prompt.Completer, filter prompt.Filter, ignoreCase bool) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		return filter(c(d), d.GetWordBeforeCursor(), ignoreCase)
+	}
+}
+
+// Limit returns a completer which returns at most n suggestions of c.
+func Limit(c prompt.Completer, n int) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		suggests := c(d)
+		if len(suggests) > n {
+			return suggests[:n]
+		}
+		return suggests
+	}
+}
+
+// Rank returns a completer which sorts the suggestions of c by less.
+// The order of suggestions which are equal to each other is kept.
+func Rank(c prompt.Completer, less func(a, b prompt.Suggest) bool) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		suggests := c(d)
+		// Copy them not to modify a slice which may be cached by c.
+		ret := make([]prompt.Suggest, len(suggests))
+		copy(ret, suggests)
+		sort.SliceStable(ret, func(i, j int) bool {
+			return less(ret[i], ret[j])
+		})
+		return ret
+	}
+}
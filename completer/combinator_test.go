@@ -0,0 +1,109 @@
+package completer
+
+import (
+	"reflect"
+	"testing"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func staticCompleter(texts ...string) prompt.Completer {
+	return func(prompt.Document) []prompt.Suggest {
+		suggests := make([]prompt.Suggest, 0, len(texts))
+		for _, t := range texts {
+			suggests = append(suggests, prompt.Suggest{Text: t})
+		}
+		return suggests
+	}
+}
+
+func TestCombinators(t *testing.T) {
+	git := staticCompleter("git-help")
+	scenarioTable := []struct {
+		name      string
+		completer prompt.Completer
+		text      string
+		expected  []string
+	}{
+		{
+			name:      "merge",
+			completer: Merge(staticCompleter("b", "a"), staticCompleter("a", "c")),
+			expected:  []string{"b", "a", "c"},
+		},
+		{
+			name:      "fallback",
+			completer: Fallback(staticCompleter(), staticCompleter("a"), staticCompleter("b")),
+			expected:  []string{"a"},
+		},
+		{
+			name:      "fallback to nothing",
+			completer: Fallback(staticCompleter()),
+			expected:  []string{},
+		},
+		{
+			name:      "by command",
+			completer: ByCommand(map[string]prompt.Completer{"git": git}, staticCompleter("git", "ls")),
+			text:      "git ",
+			expected:  []string{"git-help"},
+		},
+		{
+			name:      "by command for the command name",
+			completer: ByCommand(map[string]prompt.Completer{"git": git}, staticCompleter("git", "ls")),
+			text:      "gi",
+			expected:  []string{"git", "ls"},
+		},
+		{
+			name:      "by command for an unknown command",
+			completer: ByCommand(map[string]prompt.Completer{"git": git}, nil),
+			text:      "ls ",
+			expected:  []string{},
+		},
+		{
+			name:      "by arg index",
+			completer: ByArgIndex(staticCompleter("cmd"), nil, staticCompleter("second")),
+			text:      `cmd "first arg" s`,
+			expected:  []string{"second"},
+		},
+		{
+			name:      "by arg index out of range",
+			completer: ByArgIndex(staticCompleter("cmd")),
+			text:      "cmd ",
+			expected:  []string{},
+		},
+		{
+			name:      "with filter",
+			completer: WithFilter(staticCompleter("Apple", "banana", "apricot"), prompt.FilterHasPrefix, true),
+			text:      "ap",
+			expected:  []string{"Apple", "apricot"},
+		},
+		{
+			name:      "limit",
+			completer: Limit(staticCompleter("a", "b", "c"), 2),
+			expected:  []string{"a", "b"},
+		},
+		{
+			name: "rank",
+			completer: Rank(staticCompleter("ccc", "a", "bb", "d"), func(a, b prompt.Suggest) bool {
+				return len(a.Text) < len(b.Text)
+			}),
+			expected: []string{"a", "d", "bb", "ccc"},
+		},
+	}
+
+	for _, s := range scenarioTable {
+		if actual := suggestTexts(s.completer(newDocument(s.text))); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("[%s] Should be %#v, but got %#v", s.name, s.expected, actual)
+		}
+	}
+}
+
+func TestRankDoesNotModifySuggestions(t *testing.T) {
+	suggests := []prompt.Suggest{{Text: "b"}, {Text: "a"}}
+	c := Rank(func(prompt.Document) []prompt.Suggest { return suggests }, func(a, b prompt.Suggest) bool {
+		return a.Text < b.Text
+	})
+	c(newDocument(""))
+	if suggests[0].Text != "b" {
+		t.Errorf("Should not modify suggestions, but got %#v", suggests)
+	}
+}
@@ -0,0 +1,209 @@
+// Package command provides a declarative way to define commands, subcommands, flags and
+// positional arguments, and derives a prompt.Completer, argument validation and help
+// output from the same definition.
+package command
+
+import (
+	"strings"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// ValueType is the type of a value which a flag takes.
+type ValueType int
+
+const (
+	// Bool is a flag which doesn't take a value.
+	Bool ValueType = iota
+	// String is a flag which takes a string value.
+	String
+	// Int is a flag which takes an integer value.
+	Int
+	// Float is a flag which takes a floating point number value.
+	Float
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case String:
+		return "string"
+	case Int:
+		return "int"
+	case Float:
+		return "float"
+	default:
+		return ""
+	}
+}
+
+// ValuesFunc returns candidates of a value of a flag or a positional argument.
+// The returned suggestions are filtered by the word before the cursor.
+type ValuesFunc func(d prompt.Document) []prompt.Suggest
+
+// Flag is a flag of a command like "-n" or "--name".
+type Flag struct {
+	// Long is the name of a long form without "--".
+	Long string
+	// Short is the name of a short form without "-". It should be a single character.
+	Short       string
+	Description string
+	Type        ValueType
+	Required    bool
+	Values      ValuesFunc
+}
+
+// Arg is a positional argument of a command.
+type Arg struct {
+	Name        string
+	Description string
+	Required    bool
+	// Variadic accepts any number of arguments. It is valid only for the last argument.
+	Variadic bool
+	Values   ValuesFunc
+}
+
+// Command is a command which has subcommands, flags and positional arguments.
+// The root command of a prompt usually has an empty Name and top-level commands as Subcommands.
+type Command struct {
+	Name        string
+	Aliases     []string
+	Description string
+	Flags       []*Flag
+	Args        []*Arg
+	Subcommands []*Command
+}
+
+func (c *Command) subcommand(name string) *Command {
+	for _, sub := range c.Subcommands {
+		if sub.Name == name {
+			return sub
+		}
+		for _, a := range sub.Aliases {
+			if a == name {
+				return sub
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Command) longFlag(name string) *Flag {
+	for _, f := range c.Flags {
+		if f.Long != "" && f.Long == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (c *Command) shortFlag(name string) *Flag {
+	for _, f := range c.Flags {
+		if f.Short != "" && f.Short == name {
+			return f
+		}
+	}
+	return nil
+}
+
+func (c *Command) arg(i int) *Arg {
+	if i < len(c.Args) {
+		return c.Args[i]
+	}
+	if n := len(c.Args); n > 0 && c.Args[n-1].Variadic {
+		return c.Args[n-1]
+	}
+	return nil
+}
+
+// flagValue is a flag with its value found in the command line.
+type flagValue struct {
+	flag  *Flag
+	name  string
+	value string
+	has   bool
+}
+
+// parsed is the result of walking words of a command line.
+type parsed struct {
+	path    []*Command
+	args    []string
+	flags   []flagValue
+	unknown []string
+	// pending is a flag which is waiting for its value.
+	pending *flagValue
+}
+
+func (p *parsed) command() *Command {
+	return p.path[len(p.path)-1]
+}
+
+func parse(root *Command, words []string) *parsed {
+	p := &parsed{path: []*Command{root}}
+	noMoreFlags := false
+	for _, w := range words {
+		cmd := p.command()
+		if p.pending != nil {
+			p.pending.value, p.pending.has = w, true
+			p.flags = append(p.flags, *p.pending)
+			p.pending = nil
+			continue
+		}
+
+		switch {
+		case w == "--" && !noMoreFlags:
+			noMoreFlags = true
+		case strings.HasPrefix(w, "--") && !noMoreFlags:
+			name, value, has := w[2:], "", false
+			if i := strings.Index(name, "="); i != -1 {
+				name, value, has = name[:i], name[i+1:], true
+			}
+			f := cmd.longFlag(name)
+			if f == nil {
+				p.unknown = append(p.unknown, w)
+				continue
+			}
+			fv := flagValue{flag: f, name: w, value: value, has: has}
+			if f.Type != Bool && !has {
+				p.pending = &fv
+				continue
+			}
+			p.flags = append(p.flags, fv)
+		case strings.HasPrefix(w, "-") && len(w) > 1 && !noMoreFlags:
+			// Combined short flags like "-abc" or "-nVALUE".
+			names := []rune(w[1:])
+			for i, n := range names {
+				f := cmd.shortFlag(string(n))
+				if f == nil {
+					p.unknown = append(p.unknown, "-"+string(n))
+					break
+				}
+				fv := flagValue{flag: f, name: "-" + string(n)}
+				if f.Type != Bool {
+					if rest := string(names[i+1:]); rest != "" {
+						fv.value, fv.has = rest, true
+						p.flags = append(p.flags, fv)
+					} else {
+						p.pending = &fv
+					}
+					break
+				}
+				p.flags = append(p.flags, fv)
+			}
+		default:
+			if len(p.args) == 0 {
+				if sub := cmd.subcommand(w); sub != nil {
+					p.path = append(p.path, sub)
+					continue
+				}
+			}
+			p.args = append(p.args, w)
+		}
+	}
+	return p
+}
+
+// Find returns the deepest subcommand specified by words.
+// Flags are skipped, so Find(strings.Fields("remote -v add")) returns "add" command.
+func (c *Command) Find(words []string) *Command {
+	return parse(c, words).command()
+}
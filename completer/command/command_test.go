@@ -0,0 +1,155 @@
+package command
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func newTestCommand() *Command {
+	return &Command{
+		Subcommands: []*Command{
+			{
+				Name:        "get",
+				Description: "Display resources",
+				Flags: []*Flag{
+					{Long: "output", Short: "o", Description: "Output format", Type: String, Values: func(prompt.Document) []prompt.Suggest {
+						return []prompt.Suggest{{Text: "json"}, {Text: "yaml"}}
+					}},
+					{Long: "limit", Description: "Max items", Type: Int},
+					{Long: "watch", Short: "w", Description: "Watch for changes"},
+				},
+				Args: []*Arg{
+					{Name: "resource", Required: true, Values: func(prompt.Document) []prompt.Suggest {
+						return []prompt.Suggest{{Text: "pods"}, {Text: "services"}}
+					}},
+					{Name: "name", Variadic: true},
+				},
+			},
+			{
+				Name:        "config",
+				Aliases:     []string{"cfg"},
+				Description: "Modify config files",
+				Subcommands: []*Command{
+					{Name: "view", Description: "Display merged config"},
+					{Name: "use-context", Description: "Set the current context", Args: []*Arg{{Name: "context", Required: true}}},
+				},
+			},
+		},
+	}
+}
+
+func complete(c *Command, text string) []string {
+	buf := prompt.NewBuffer()
+	buf.InsertText(text, false, true)
+	d := *buf.Document()
+
+	var texts []string
+	for _, s := range c.Complete(d) {
+		texts = append(texts, s.Text)
+	}
+	return texts
+}
+
+func TestComplete(t *testing.T) {
+	c := newTestCommand()
+	scenarioTable := []struct {
+		text     string
+		expected []string
+	}{
+		{text: "", expected: []string{"get", "config"}},
+		{text: "co", expected: []string{"config"}},
+		{text: "cfg ", expected: []string{"view", "use-context"}},
+		{text: "get ", expected: []string{"pods", "services"}},
+		{text: "get -", expected: []string{"--output", "--limit", "--watch", "-o", "-w"}},
+		{text: "get --o", expected: []string{"--output"}},
+		{text: "get -o ", expected: []string{"json", "yaml"}},
+		{text: "get --output y", expected: []string{"yaml"}},
+		{text: "get -w s", expected: []string{"services"}},
+		{text: "get --output=", expected: []string{"json", "yaml"}},
+		{text: "get --output=J", expected: []string{"json"}},
+		{text: "get --limit=", expected: nil},
+		{text: "get --OUT", expected: []string{"--output"}},
+		{text: "\t", expected: []string{"get", "config"}},
+		{text: "get\n", expected: []string{"pods", "services"}},
+		{text: "get --output 'y", expected: []string{"yaml"}},
+		{text: `get --output="j`, expected: []string{"json"}},
+		{text: "get pods ", expected: nil},
+	}
+	for _, s := range scenarioTable {
+		if actual := complete(c, s.text); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("%q: Should be %#v, but got %#v", s.text, s.expected, actual)
+		}
+	}
+}
+
+func TestCompleteFlagValueRange(t *testing.T) {
+	buf := prompt.NewBuffer()
+	buf.InsertText("get --output=js", false, true)
+	expected := []prompt.Suggest{{Text: "json", Range: &prompt.SuggestRange{Start: -2}}}
+	if actual := newTestCommand().Complete(*buf.Document()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestCompleteQuotedFlagValueRange(t *testing.T) {
+	buf := prompt.NewBuffer()
+	buf.InsertText(`get --output="js`, false, true)
+	expected := []prompt.Suggest{{Text: "json", Range: &prompt.SuggestRange{Start: -3}}}
+	if actual := newTestCommand().Complete(*buf.Document()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := newTestCommand()
+	scenarioTable := []struct {
+		line    string
+		command string
+		err     string
+	}{
+		{line: "get pods a b", command: "get"},
+		{line: "get -wo json pods", command: "get"},
+		{line: "get --limit=10 pods", command: "get"},
+		{line: "get", command: "get", err: "get: missing argument <resource>"},
+		{line: "get --limit x pods", command: "get", err: `get: invalid value "x" for flag --limit: must be int`},
+		{line: "get pods -o", command: "get", err: "get: flag -o needs a value"},
+		{line: "get --all pods", command: "get", err: "get: unknown flag --all"},
+		{line: "config edit", command: "config", err: `config: unknown command "edit"`},
+		{line: "config view extra", command: "view", err: "config view: too many arguments"},
+	}
+	for _, s := range scenarioTable {
+		cmd, err := c.ValidateLine(s.line)
+		if cmd.Name != s.command {
+			t.Errorf("%q: Should be %q, but got %q", s.line, s.command, cmd.Name)
+		}
+		if (err == nil && s.err != "") || (err != nil && err.Error() != s.err) {
+			t.Errorf("%q: Should be %q, but got %v", s.line, s.err, err)
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	c := newTestCommand()
+	expected := `Usage: get [flags] <resource> [name...]
+
+Display resources
+
+Arguments:
+  resource
+  name
+
+Flags:
+  -o, --output <string>  Output format
+  --limit <int>          Max items
+  -w, --watch            Watch for changes
+`
+	if actual := c.Help("get", "pods"); actual != expected {
+		t.Errorf("Should be %q, but got %q", expected, actual)
+	}
+	if actual := c.Help("cfg"); !strings.Contains(actual, "Usage: config <command>") || !strings.Contains(actual, "use-context  Set the current context") {
+		t.Errorf("Unexpected help %q", actual)
+	}
+}
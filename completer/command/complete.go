@@ -0,0 +1,87 @@
+package command
+
+import (
+	"strings"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// ignoreCase is whether subcommands, flags and values are filtered ignoring case.
+const ignoreCase = true
+
+// Completer returns prompt.Completer which completes subcommands, flags and values of them.
+func (c *Command) Completer() prompt.Completer {
+	return c.Complete
+}
+
+// Complete returns suggestions for the document.
+// Arguments are split like a POSIX shell, so quoted arguments may contain white spaces.
+func (c *Command) Complete(d prompt.Document) []prompt.Suggest {
+	before := d.TextBeforeCursor()
+	buf := prompt.NewBuffer()
+	buf.InsertText(before, false, true)
+	words := buf.Document().ShellArgs()[:d.ShellArgIndex()]
+	current, _ := d.ShellWordBeforeCursor()
+	p := parse(c, words)
+	cmd := p.command()
+
+	if p.pending != nil {
+		return values(p.pending.flag.Values, d, current)
+	}
+	if i := strings.Index(current, "="); i != -1 && strings.HasPrefix(current, "--") {
+		// Complete the value of "--flag=value", replacing only the value part.
+		f := cmd.longFlag(current[2:i])
+		if f == nil {
+			return nil
+		}
+		// The range is the value as typed, which may be quoted.
+		raw := before[d.FindStartOfPreviousShellWord():]
+		raw = raw[strings.Index(raw, "=")+1:]
+		suggests := values(f.Values, d, current[i+1:])
+		r := &prompt.SuggestRange{Start: -len([]rune(raw))}
+		for i := range suggests {
+			suggests[i].Range = r
+		}
+		return suggests
+	}
+	if strings.HasPrefix(current, "-") {
+		return prompt.FilterHasPrefix(flagSuggestions(cmd), current, ignoreCase)
+	}
+
+	var suggests []prompt.Suggest
+	if len(p.args) == 0 {
+		for _, sub := range cmd.Subcommands {
+			suggests = append(suggests, prompt.Suggest{Text: sub.Name, Description: sub.Description})
+		}
+	}
+	if a := cmd.arg(len(p.args)); a != nil && a.Values != nil {
+		suggests = append(suggests, a.Values(d)...)
+	}
+	return prompt.FilterHasPrefix(suggests, current, ignoreCase)
+}
+
+// values returns the values of fn filtered by current. It returns a copy which callers may modify.
+func values(fn ValuesFunc, d prompt.Document, current string) []prompt.Suggest {
+	if fn == nil {
+		return nil
+	}
+	filtered := prompt.FilterHasPrefix(fn(d), current, ignoreCase)
+	suggests := make([]prompt.Suggest, len(filtered))
+	copy(suggests, filtered)
+	return suggests
+}
+
+func flagSuggestions(cmd *Command) []prompt.Suggest {
+	suggests := make([]prompt.Suggest, 0, len(cmd.Flags)*2)
+	for _, f := range cmd.Flags {
+		if f.Long != "" {
+			suggests = append(suggests, prompt.Suggest{Text: "--" + f.Long, Description: f.Description})
+		}
+	}
+	for _, f := range cmd.Flags {
+		if f.Short != "" {
+			suggests = append(suggests, prompt.Suggest{Text: "-" + f.Short, Description: f.Description})
+		}
+	}
+	return suggests
+}
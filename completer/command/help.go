@@ -0,0 +1,103 @@
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Help returns the help message of the deepest subcommand specified by words.
+func (c *Command) Help(words ...string) string {
+	p := parse(c, words)
+	cmd := p.command()
+
+	var b strings.Builder
+	b.WriteString("Usage:")
+	if name := commandPath(p.path); name != "" {
+		b.WriteString(" " + name)
+	}
+	if len(cmd.Subcommands) > 0 {
+		b.WriteString(" <command>")
+	}
+	if len(cmd.Flags) > 0 {
+		b.WriteString(" [flags]")
+	}
+	for _, a := range cmd.Args {
+		b.WriteString(" " + argUsage(a))
+	}
+	b.WriteString("\n")
+
+	if cmd.Description != "" {
+		b.WriteString("\n" + cmd.Description + "\n")
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		b.WriteString("\nCommands:\n")
+		w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		for _, sub := range cmd.Subcommands {
+			writeRow(w, sub.Name, sub.Description)
+		}
+		_ = w.Flush()
+	}
+
+	if len(cmd.Args) > 0 {
+		b.WriteString("\nArguments:\n")
+		w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		for _, a := range cmd.Args {
+			writeRow(w, a.Name, a.Description)
+		}
+		_ = w.Flush()
+	}
+
+	if len(cmd.Flags) > 0 {
+		b.WriteString("\nFlags:\n")
+		w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+		for _, f := range cmd.Flags {
+			writeRow(w, flagUsage(f), flagDescription(f))
+		}
+		_ = w.Flush()
+	}
+	return b.String()
+}
+
+func writeRow(w io.Writer, name, description string) {
+	if description == "" {
+		fmt.Fprintf(w, "  %s\n", name)
+		return
+	}
+	fmt.Fprintf(w, "  %s\t%s\n", name, description)
+}
+
+func argUsage(a *Arg) string {
+	s := a.Name
+	if a.Variadic {
+		s += "..."
+	}
+	if a.Required {
+		return "<" + s + ">"
+	}
+	return "[" + s + "]"
+}
+
+func flagUsage(f *Flag) string {
+	var names []string
+	if f.Short != "" {
+		names = append(names, "-"+f.Short)
+	}
+	if f.Long != "" {
+		names = append(names, "--"+f.Long)
+	}
+	s := strings.Join(names, ", ")
+	if f.Type != Bool {
+		s += " <" + f.Type.String() + ">"
+	}
+	return s
+}
+
+func flagDescription(f *Flag) string {
+	if f.Required {
+		return f.Description + " (required)"
+	}
+	return f.Description
+}
@@ -0,0 +1,97 @@
+package command
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Validate parses words of a command line and returns an error if they don't match the definition,
+// e.g. unknown flags, missing or invalid values and missing or too many arguments.
+// It returns the deepest subcommand specified by words too.
+func (c *Command) Validate(words []string) (*Command, error) {
+	p := parse(c, words)
+	cmd := p.command()
+
+	if len(p.unknown) > 0 {
+		return cmd, newError(p.path, "unknown flag %s", p.unknown[0])
+	}
+	if p.pending != nil {
+		return cmd, newError(p.path, "flag %s needs a value", p.pending.name)
+	}
+	for _, fv := range p.flags {
+		if err := validateValue(fv.flag.Type, fv.value); err != nil {
+			return cmd, newError(p.path, "invalid value %q for flag %s: %s", fv.value, fv.name, err)
+		}
+	}
+	for _, f := range cmd.Flags {
+		if f.Required && !hasFlag(p.flags, f) {
+			return cmd, newError(p.path, "required flag %s is not set", flagName(f))
+		}
+	}
+
+	if len(p.args) > 0 && len(cmd.Args) == 0 && len(cmd.Subcommands) > 0 {
+		return cmd, newError(p.path, "unknown command %q", p.args[0])
+	}
+	for i, a := range cmd.Args {
+		if a.Required && len(p.args) <= i {
+			return cmd, newError(p.path, "missing argument <%s>", a.Name)
+		}
+	}
+	if len(p.args) > len(cmd.Args) && cmd.arg(len(p.args)-1) == nil {
+		return cmd, newError(p.path, "too many arguments")
+	}
+	return cmd, nil
+}
+
+// ValidateLine splits the line by white spaces and validates it like Validate.
+func (c *Command) ValidateLine(line string) (*Command, error) {
+	return c.Validate(strings.Fields(line))
+}
+
+func validateValue(t ValueType, v string) error {
+	var err error
+	switch t {
+	case Int:
+		_, err = strconv.Atoi(v)
+	case Float:
+		_, err = strconv.ParseFloat(v, 64)
+	}
+	if err != nil {
+		return fmt.Errorf("must be %s", t)
+	}
+	return nil
+}
+
+func hasFlag(flags []flagValue, f *Flag) bool {
+	for _, fv := range flags {
+		if fv.flag == f {
+			return true
+		}
+	}
+	return false
+}
+
+func flagName(f *Flag) string {
+	if f.Long != "" {
+		return "--" + f.Long
+	}
+	return "-" + f.Short
+}
+
+func newError(path []*Command, format string, a ...interface{}) error {
+	if name := commandPath(path); name != "" {
+		return fmt.Errorf(name+": "+format, a...)
+	}
+	return fmt.Errorf(format, a...)
+}
+
+func commandPath(path []*Command) string {
+	names := make([]string, 0, len(path))
+	for _, c := range path {
+		if c.Name != "" {
+			names = append(names, c.Name)
+		}
+	}
+	return strings.Join(names, " ")
+}
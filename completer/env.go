@@ -0,0 +1,74 @@
+package completer
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// EnvCompleter is a completer for environment variables like `$HOME` or `${HOME}`.
+// The description of each suggestion is the value of the variable.
+// It returns nothing unless the text before the cursor ends with a variable reference.
+type EnvCompleter struct {
+	IgnoreCase bool
+}
+
+// Complete returns environment variables starting with the name before the cursor.
+func (c *EnvCompleter) Complete(d prompt.Document) []prompt.Suggest {
+	name, braced, ok := envNameBeforeCursor(d.TextBeforeCursor())
+	if !ok {
+		return nil
+	}
+
+	prefix := "$"
+	if braced {
+		prefix = "${"
+	}
+
+	env := os.Environ()
+	sort.Strings(env)
+	suggests := make([]prompt.Suggest, 0, len(env))
+	for _, kv := range env {
+		i := strings.IndexRune(kv, '=')
+		if i <= 0 {
+			continue // e.g. "=C:=C:\" on Windows
+		}
+		text := prefix + kv[:i]
+		if braced {
+			text += "}"
+		}
+		suggests = append(suggests, prompt.Suggest{
+			Text:        text,
+			DisplayText: kv[:i],
+			Description: kv[i+1:],
+		})
+	}
+	suggests = prompt.FilterHasPrefix(suggests, prefix+name, c.IgnoreCase)
+
+	// Replace from "$" so that the variable can be completed in the middle of a word like `--dir=$HO`.
+	r := &prompt.SuggestRange{Start: -len(prefix) - len([]rune(name))}
+	for i := range suggests {
+		suggests[i].Range = r
+	}
+	return suggests
+}
+
+// envNameBeforeCursor returns the name of the variable reference at the end of text.
+func envNameBeforeCursor(text string) (name string, braced bool, ok bool) {
+	i := strings.LastIndexByte(text, '$')
+	if i < 0 || (i > 0 && text[i-1] == '\\') {
+		return "", false, false
+	}
+	name = text[i+1:]
+	if strings.HasPrefix(name, "{") {
+		name, braced = name[1:], true
+	}
+	for _, r := range name {
+		if r != '_' && !('a' <= r && r <= 'z') && !('A' <= r && r <= 'Z') && !('0' <= r && r <= '9') {
+			return "", false, false
+		}
+	}
+	return name, braced, true
+}
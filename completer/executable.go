@@ -0,0 +1,128 @@
+package completer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// ExecutableCompleter is a completer for executables found on $PATH.
+// The description of each suggestion is the directory containing it.
+// Executables are cached until $PATH or the modification time of one of its directories changes.
+type ExecutableCompleter struct {
+	IgnoreCase bool
+
+	mu       sync.Mutex
+	path     string
+	modTimes []time.Time
+	cache    []prompt.Suggest
+}
+
+// Complete returns executables starting with the word before the cursor.
+func (c *ExecutableCompleter) Complete(d prompt.Document) []prompt.Suggest {
+	return copySuggests(prompt.FilterHasPrefix(c.executables(), d.GetWordBeforeCursor(), c.IgnoreCase))
+}
+
+// copySuggests returns a copy of suggests, so that callers cannot modify a cached slice.
+// FilterHasPrefix and the other filters return the given slice as it is for an empty word.
+func copySuggests(suggests []prompt.Suggest) []prompt.Suggest {
+	if suggests == nil {
+		return nil
+	}
+	ret := make([]prompt.Suggest, len(suggests))
+	copy(ret, suggests)
+	return ret
+}
+
+func (c *ExecutableCompleter) executables() []prompt.Suggest {
+	path := os.Getenv("PATH")
+	dirs := filepath.SplitList(path)
+	modTimes := make([]time.Time, len(dirs))
+	for i := range dirs {
+		if fi, err := os.Stat(dirs[i]); err == nil {
+			modTimes[i] = fi.ModTime()
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache != nil && c.path == path && equalTimes(c.modTimes, modTimes) {
+		return c.cache
+	}
+
+	seen := make(map[string]struct{})
+	suggests := make([]prompt.Suggest, 0, 256)
+	for _, dir := range dirs {
+		files, err := ioutil.ReadDir(dir)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				debug.Log("completer: cannot read directory items:" + err.Error())
+			}
+			continue
+		}
+		for _, f := range files {
+			name, ok := executableName(dir, f)
+			if !ok {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue // The first one on $PATH is used.
+			}
+			seen[name] = struct{}{}
+			suggests = append(suggests, prompt.Suggest{Text: name, Description: dir})
+		}
+	}
+	c.path, c.modTimes, c.cache = path, modTimes, suggests
+	return suggests
+}
+
+// executableName returns the name of f as a command if it is executable.
+func executableName(dir string, f os.FileInfo) (string, bool) {
+	if f.Mode()&os.ModeSymlink != 0 {
+		target, err := os.Stat(filepath.Join(dir, f.Name()))
+		if err != nil {
+			return "", false
+		}
+		f = target
+	}
+	if f.IsDir() {
+		return "", false
+	}
+
+	if runtime.GOOS != "windows" {
+		return f.Name(), f.Mode()&0111 != 0
+	}
+	ext := strings.ToLower(filepath.Ext(f.Name()))
+	if ext == "" {
+		return "", false
+	}
+	pathExt := os.Getenv("PATHEXT")
+	if pathExt == "" {
+		pathExt = ".com;.exe;.bat;.cmd"
+	}
+	for _, e := range filepath.SplitList(strings.ToLower(pathExt)) {
+		if ext == e {
+			return strings.TrimSuffix(f.Name(), f.Name()[len(f.Name())-len(ext):]), true
+		}
+	}
+	return "", false
+}
+
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
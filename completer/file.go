@@ -1,11 +1,15 @@
 package completer
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"os/user"
 	"path/filepath"
 	"runtime"
+	"strings"
+	"sync"
+	"time"
 
 	prompt "github.com/c-bata/go-prompt"
 	"github.com/c-bata/go-prompt/internal/debug"
@@ -13,16 +17,34 @@ import (
 
 var (
 	// FilePathCompletionSeparator holds separate characters.
+	// FilePathCompleter replaces only the last element of a path by itself,
+	// so you don't need to set OptionCompletionWordSeparator(completer.FilePathCompletionSeparator) anymore.
 	FilePathCompletionSeparator = string([]byte{' ', os.PathSeparator})
 )
 
 // FilePathCompleter is a completer for your local file system.
-// Please caution that you need to set OptionCompletionWordSeparator(completer.FilePathCompletionSeparator)
-// when you use this completer.
+// It understands quoted and escaped paths like `"my dir/fi` or `my\ dir/fi`,
+// `~/` and `~user/` prefixes, environment variables and glob patterns like `*.go`.
+// Directory entries are cached until the modification time of the directory changes.
 type FilePathCompleter struct {
-	Filter        func(fi os.FileInfo) bool
-	IgnoreCase    bool
-	fileListCache map[string][]prompt.Suggest
+	Filter     func(fi os.FileInfo) bool
+	IgnoreCase bool
+	// HideHiddenFiles hides files starting with "." unless the typed name starts with ".".
+	HideHiddenFiles bool
+
+	mu            sync.Mutex
+	fileListCache map[string]fileList
+}
+
+type fileList struct {
+	modTime time.Time
+	entries []fileEntry
+}
+
+type fileEntry struct {
+	name        string
+	isDir       bool
+	description string
 }
 
 func cleanFilePath(path string) (dir, base string, err error) {
@@ -35,12 +57,10 @@ func cleanFilePath(path string) (dir, base string, err error) {
 		endsWithSeparator = true
 	}
 
-	if runtime.GOOS != "windows" && len(path) >= 2 && path[0:2] == "~/" {
-		me, err := user.Current()
-		if err != nil {
+	if runtime.GOOS != "windows" && len(path) >= 2 && path[0] == '~' {
+		if path, err = expandHomeDir(path); err != nil {
 			return "", "", err
 		}
-		path = filepath.Join(me.HomeDir, path[1:])
 	}
 	path = filepath.Clean(os.ExpandEnv(path))
 	dir = filepath.Dir(path)
@@ -53,38 +73,171 @@ func cleanFilePath(path string) (dir, base string, err error) {
 	return dir, base, nil
 }
 
+// expandHomeDir expands "~/" to the home directory of the current user
+// and "~name/" to the one of the named user.
+func expandHomeDir(path string) (string, error) {
+	i := strings.IndexRune(path, os.PathSeparator)
+	if i < 0 {
+		return path, nil
+	}
+	var u *user.User
+	var err error
+	if i == 1 {
+		u, err = user.Current()
+	} else {
+		u, err = user.Lookup(path[1:i])
+	}
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(u.HomeDir, path[i:]), nil
+}
+
 // Complete returns suggestions from your local file system.
 func (c *FilePathCompleter) Complete(d prompt.Document) []prompt.Suggest {
-	if c.fileListCache == nil {
-		c.fileListCache = make(map[string][]prompt.Suggest, 4)
+	path, quote := d.ShellWordBeforeCursor()
+	dir, base, err := cleanFilePath(path)
+	if err != nil {
+		debug.Log("completer: cannot expand home directory:" + err.Error())
+		return nil
 	}
 
-	path := d.GetWordBeforeCursor()
-	dir, base, err := cleanFilePath(path)
+	entries, err := c.readDir(dir)
 	if err != nil {
-		debug.Log("completer: cannot get current user:" + err.Error())
+		if !os.IsNotExist(err) {
+			debug.Log("completer: cannot read directory items:" + err.Error())
+		}
 		return nil
 	}
 
-	if cached, ok := c.fileListCache[dir]; ok {
-		return prompt.FilterHasPrefix(cached, base, c.IgnoreCase)
+	// Replace only the last element of the path, keeping its quotes and escapes as typed.
+	raw := d.TextBeforeCursor()[d.FindStartOfPreviousShellWord():]
+	var opening string
+	if i := strings.LastIndexAny(raw, "/"+string(os.PathSeparator)); i >= 0 {
+		raw = raw[i+1:]
+	} else if quote != prompt.ShellQuoteNone && strings.HasPrefix(raw, quoteChar(quote)) {
+		opening = raw[:1]
 	}
+	r := &prompt.SuggestRange{Start: -len([]rune(raw))}
 
-	files, err := ioutil.ReadDir(dir)
-	if err != nil && os.IsNotExist(err) {
-		return nil
-	} else if err != nil {
-		debug.Log("completer: cannot read directory items:" + err.Error())
-		return nil
+	isGlob := strings.ContainsAny(base, "*?[")
+	suggests := make([]prompt.Suggest, 0, len(entries))
+	for _, e := range entries {
+		if c.HideHiddenFiles && strings.HasPrefix(e.name, ".") && !strings.HasPrefix(base, ".") {
+			continue
+		}
+		if !c.match(e.name, base, isGlob) {
+			continue
+		}
+		s := prompt.Suggest{
+			Text:        opening + quoteFileName(e.name, quote),
+			DisplayText: e.name,
+			Description: e.description,
+			Range:       r,
+		}
+		if e.isDir {
+			s.DisplayText += string(os.PathSeparator)
+			s.AppendText = string(os.PathSeparator)
+		} else if quote != prompt.ShellQuoteNone {
+			s.AppendText = quoteChar(quote)
+		}
+		suggests = append(suggests, s)
 	}
+	return suggests
+}
 
-	suggests := make([]prompt.Suggest, 0, len(files))
+func (c *FilePathCompleter) match(name, base string, isGlob bool) bool {
+	if c.IgnoreCase {
+		name = strings.ToUpper(name)
+		base = strings.ToUpper(base)
+	}
+	if isGlob {
+		ok, err := filepath.Match(base, name)
+		return err == nil && ok
+	}
+	return strings.HasPrefix(name, base)
+}
+
+// readDir returns the entries of dir, which are cached until its modification time changes.
+func (c *FilePathCompleter) readDir(dir string) ([]fileEntry, error) {
+	fi, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.fileListCache == nil {
+		c.fileListCache = make(map[string]fileList, 4)
+	}
+	if cached, ok := c.fileListCache[dir]; ok && cached.modTime.Equal(fi.ModTime()) {
+		return cached.entries, nil
+	}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]fileEntry, 0, len(files))
 	for _, f := range files {
+		if f.Mode()&os.ModeSymlink != 0 {
+			if target, err := os.Stat(filepath.Join(dir, f.Name())); err == nil {
+				f = target
+			}
+		}
 		if c.Filter != nil && !c.Filter(f) {
 			continue
 		}
-		suggests = append(suggests, prompt.Suggest{Text: f.Name()})
+		entries = append(entries, fileEntry{
+			name:        f.Name(),
+			isDir:       f.IsDir(),
+			description: describeFile(f),
+		})
+	}
+	c.fileListCache[dir] = fileList{modTime: fi.ModTime(), entries: entries}
+	return entries, nil
+}
+
+func quoteFileName(name string, quote prompt.ShellQuote) string {
+	q := prompt.QuoteShellWord(name, quote)
+	if quote == prompt.ShellQuoteNone {
+		return q
+	}
+	return q[1 : len(q)-1]
+}
+
+func quoteChar(quote prompt.ShellQuote) string {
+	if quote == prompt.ShellQuoteSingle {
+		return "'"
+	}
+	return `"`
+}
+
+func describeFile(fi os.FileInfo) string {
+	switch m := fi.Mode(); {
+	case m.IsDir():
+		return "directory"
+	case m&os.ModeSymlink != 0:
+		return "symlink"
+	case m&os.ModeNamedPipe != 0:
+		return "named pipe"
+	case m&os.ModeSocket != 0:
+		return "socket"
+	case m&os.ModeDevice != 0:
+		return "device"
+	}
+	return "file, " + formatFileSize(fi.Size())
+}
+
+func formatFileSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
 	}
-	c.fileListCache[dir] = suggests
-	return prompt.FilterHasPrefix(suggests, base, c.IgnoreCase)
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
 }
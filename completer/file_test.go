@@ -0,0 +1,171 @@
+package completer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func newTestDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "go-prompt-completer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"my dir", ".hidden"} {
+		if err := os.Mkdir(filepath.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range map[string]string{
+		"main.go":             "package main\n",
+		"main_test.go":        "",
+		"README.md":           "",
+		"my dir/my file.txt":  "hello",
+		"my dir/it's.txt":     "",
+		"my dir/another.json": "{}",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// completeFilePath returns the lines after accepting each suggestion for text.
+func completeFilePath(c *FilePathCompleter, text string) []string {
+	buf := prompt.NewBuffer()
+	buf.InsertText(text, false, true)
+	d := *buf.Document()
+
+	var lines []string
+	for _, s := range c.Complete(d) {
+		r := []rune(text)
+		lines = append(lines, string(r[:len(r)+s.Range.Start])+s.Text+s.AppendText)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestFilePathCompleter(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	scenarioTable := []struct {
+		text       string
+		hideHidden bool
+		expected   []string
+	}{
+		{
+			text:     "cat ma",
+			expected: []string{"cat main.go", "cat main_test.go"},
+		},
+		{
+			text:     "cat ",
+			expected: []string{"cat .hidden/", "cat README.md", "cat main.go", "cat main_test.go", "cat my\\ dir/"},
+		},
+		{
+			text:       "cat ",
+			hideHidden: true,
+			expected:   []string{"cat README.md", "cat main.go", "cat main_test.go", "cat my\\ dir/"},
+		},
+		{
+			text:       "cat .h",
+			hideHidden: true,
+			expected:   []string{"cat .hidden/"},
+		},
+		{
+			text:     "cat *_test.go",
+			expected: []string{"cat main_test.go"},
+		},
+		{
+			text:     "cat my\\ dir/my",
+			expected: []string{"cat my\\ dir/my\\ file.txt"},
+		},
+		{
+			text:     `cat "my dir/my`,
+			expected: []string{`cat "my dir/my file.txt"`},
+		},
+		{
+			text:     `cat "my`,
+			expected: []string{`cat "my dir/`},
+		},
+		{
+			text:     `cat 'my dir/it`,
+			expected: []string{`cat 'my dir/it'\''s.txt'`},
+		},
+		{
+			text:     "cat ./my\\ dir/*.json",
+			expected: []string{"cat ./my\\ dir/another.json"},
+		},
+	}
+
+	for i, s := range scenarioTable {
+		c := &FilePathCompleter{HideHiddenFiles: s.hideHidden}
+		if actual := completeFilePath(c, s.text); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("[%d] %q: Should be %#v, but got %#v", i, s.text, s.expected, actual)
+		}
+	}
+}
+
+func TestFilePathCompleterDescription(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	buf := prompt.NewBuffer()
+	buf.InsertText(filepath.Join(dir, "m"), false, true)
+	c := &FilePathCompleter{}
+	actual := make(map[string]string)
+	for _, s := range c.Complete(*buf.Document()) {
+		actual[s.DisplayText] = s.Description
+	}
+	expected := map[string]string{
+		"main.go":      "file, 13 B",
+		"main_test.go": "file, 0 B",
+		"my dir/":      "directory",
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+
+	if actual := formatFileSize(1536); actual != "1.5 KB" {
+		t.Errorf("Should be %#v, but got %#v", "1.5 KB", actual)
+	}
+}
+
+func TestFilePathCompleterCacheInvalidation(t *testing.T) {
+	dir := newTestDir(t)
+	defer os.RemoveAll(dir)
+
+	c := &FilePathCompleter{}
+	text := dir + string(os.PathSeparator) + "new"
+	if actual := completeFilePath(c, text); len(actual) != 0 {
+		t.Errorf("Should be empty, but got %#v", actual)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "new.txt"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Make sure the modification time changes even on file systems with a coarse resolution.
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(dir, future, future); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{text + ".txt"}
+	if actual := completeFilePath(c, text); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+}
@@ -0,0 +1,233 @@
+package completer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// Frecency learns which suggestions are accepted, and ranks suggestions by frequency and recency.
+// Register Record by prompt.OptionAcceptHook and wrap completers by Rank:
+//
+//	f := &completer.Frecency{Path: filepath.Join(home, ".myapp_frecency.json")}
+//	p := prompt.New(executor, f.Rank(completer), prompt.OptionAcceptHook(f.Record))
+//	defer f.Save()
+//
+// Statistics are kept per context, so "main" accepted for `git checkout` is not ranked up for `git push`.
+// They are saved in background shortly after Record, and Save should be called before exit
+// not to lose the latest ones.
+type Frecency struct {
+	// Path is the JSON file where statistics are persisted. They are kept only in memory if empty.
+	Path string
+	// Context returns the context of a document. KeyByArgIndex is used if nil.
+	Context func(d prompt.Document) string
+	// HalfLife is how long it takes for an acceptance to weigh half. It is 7 days if 0.
+	HalfLife time.Duration
+	// MaxEntries is the number of suggestions kept in each context. The lowest scored one is dropped if exceeded.
+	// It is 100 if 0.
+	MaxEntries int
+
+	mu        sync.Mutex
+	loaded    bool
+	stats     map[string]map[string]*frecencyStat
+	now       func() time.Time
+	dirty     bool
+	saveTimer *time.Timer
+	// saveMu serializes writing the file so that older statistics never overwrite newer ones.
+	saveMu sync.Mutex
+}
+
+// frecencySaveDelay is how long Record waits before saving, so that statistics
+// recorded in a row are saved at once outside the input loop.
+const frecencySaveDelay = time.Second
+
+type frecencyStat struct {
+	Count int       `json:"count"`
+	Last  time.Time `json:"last"`
+}
+
+func (f *Frecency) context(d prompt.Document) string {
+	if f.Context != nil {
+		return f.Context(d)
+	}
+	return KeyByArgIndex(d)
+}
+
+func (f *Frecency) init() {
+	if f.now == nil {
+		f.now = time.Now
+	}
+	if f.loaded {
+		return
+	}
+	f.loaded = true
+	f.stats = make(map[string]map[string]*frecencyStat)
+	if f.Path == "" {
+		return
+	}
+	b, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			debug.Log("completer: cannot read frecency file:" + err.Error())
+		}
+		return
+	}
+	if err := json.Unmarshal(b, &f.stats); err != nil {
+		debug.Log("completer: broken frecency file:" + err.Error())
+		f.stats = make(map[string]map[string]*frecencyStat)
+	}
+}
+
+// Record records that s is accepted in the document, and saves statistics to Path in background.
+// It has the signature of prompt.AcceptHook.
+func (f *Frecency) Record(d prompt.Document, s prompt.Suggest) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.init()
+
+	ctx := f.context(d)
+	stats, ok := f.stats[ctx]
+	if !ok {
+		stats = make(map[string]*frecencyStat)
+		f.stats[ctx] = stats
+	}
+	stat, ok := stats[s.Text]
+	if !ok {
+		stat = &frecencyStat{}
+		stats[s.Text] = stat
+	}
+	stat.Count++
+	stat.Last = f.now()
+	f.prune(stats)
+
+	f.dirty = true
+	if f.Path != "" && f.saveTimer == nil {
+		f.saveTimer = time.AfterFunc(frecencySaveDelay, func() {
+			if err := f.Save(); err != nil {
+				debug.Log("completer: cannot save frecency file:" + err.Error())
+			}
+		})
+	}
+}
+
+// prune drops the lowest scored suggestions exceeding MaxEntries.
+func (f *Frecency) prune(stats map[string]*frecencyStat) {
+	max := f.MaxEntries
+	if max == 0 {
+		max = 100
+	}
+	if len(stats) <= max {
+		return
+	}
+	texts := make([]string, 0, len(stats))
+	for text := range stats {
+		texts = append(texts, text)
+	}
+	sort.Slice(texts, func(i, j int) bool {
+		return f.score(stats[texts[i]]) > f.score(stats[texts[j]])
+	})
+	for _, text := range texts[max:] {
+		delete(stats, text)
+	}
+}
+
+// Save saves statistics to Path now if they are changed since the last successful save.
+func (f *Frecency) Save() error {
+	f.saveMu.Lock()
+	defer f.saveMu.Unlock()
+
+	f.mu.Lock()
+	if f.saveTimer != nil {
+		f.saveTimer.Stop()
+		f.saveTimer = nil
+	}
+	if !f.dirty || f.Path == "" {
+		f.mu.Unlock()
+		return nil
+	}
+	b, err := json.Marshal(f.stats)
+	// Statistics recorded while writing are marked dirty again by Record.
+	f.dirty = false
+	f.mu.Unlock()
+	if err == nil {
+		err = writeFileAtomic(f.Path, b)
+	}
+	if err != nil {
+		// Keep them dirty so that they are saved next time.
+		f.mu.Lock()
+		f.dirty = true
+		f.mu.Unlock()
+	}
+	return err
+}
+
+// writeFileAtomic writes to a temporary file and renames it not to break the file if the process is killed.
+func writeFileAtomic(path string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// score is the number of acceptances, which decays by half every HalfLife since the last one.
+func (f *Frecency) score(stat *frecencyStat) float64 {
+	if stat == nil {
+		return 0
+	}
+	halfLife := f.HalfLife
+	if halfLife == 0 {
+		halfLife = 7 * 24 * time.Hour
+	}
+	age := f.now().Sub(stat.Last)
+	if age < 0 {
+		age = 0
+	}
+	return float64(stat.Count) * math.Pow(0.5, float64(age)/float64(halfLife))
+}
+
+// Score returns the frecency score of the suggestion text in the document. It is 0 if never accepted.
+func (f *Frecency) Score(d prompt.Document, text string) float64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.init()
+	return f.score(f.stats[f.context(d)][text])
+}
+
+// Rank returns a completer which sorts the suggestions of c by the frecency score.
+// Suggestions which have never been accepted keep their order after the others.
+func (f *Frecency) Rank(c prompt.Completer) prompt.Completer {
+	return func(d prompt.Document) []prompt.Suggest {
+		f.mu.Lock()
+		f.init()
+		stats := f.stats[f.context(d)]
+		scores := make(map[string]float64, len(stats))
+		for text, stat := range stats {
+			scores[text] = f.score(stat)
+		}
+		f.mu.Unlock()
+		if len(scores) == 0 {
+			return c(d)
+		}
+		return Rank(c, func(a, b prompt.Suggest) bool {
+			return scores[a.Text] > scores[b.Text]
+		})(d)
+	}
+}
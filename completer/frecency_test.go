@@ -0,0 +1,105 @@
+package completer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func TestFrecency(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-prompt-frecency")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Now()
+	clock := func() time.Time { return now }
+	path := filepath.Join(dir, "frecency.json")
+	f := &Frecency{Path: path, HalfLife: time.Hour, now: clock}
+	c := f.Rank(staticCompleter("develop", "feature", "main", "master"))
+
+	if actual := suggestTexts(c(newDocument("git checkout "))); !reflect.DeepEqual(actual, []string{"develop", "feature", "main", "master"}) {
+		t.Errorf("Should keep the order without statistics, but got %#v", actual)
+	}
+
+	// "main" is accepted twice two hours ago, and "feature" once now.
+	now = now.Add(-2 * time.Hour)
+	f.Record(newDocument("git checkout m"), prompt.Suggest{Text: "main"})
+	f.Record(newDocument("git checkout ma"), prompt.Suggest{Text: "main"})
+	now = now.Add(2 * time.Hour)
+	f.Record(newDocument("git checkout f"), prompt.Suggest{Text: "feature"})
+	f.Record(newDocument("git push origin "), prompt.Suggest{Text: "master"})
+
+	// The score of "main" is 2 * 0.25 = 0.5 while the one of "feature" is 1.
+	expected := []string{"feature", "main", "develop", "master"}
+	if actual := suggestTexts(c(newDocument("git checkout "))); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+	if actual := f.Score(newDocument("git checkout "), "main"); actual != 0.5 {
+		t.Errorf("Should be %v, but got %v", 0.5, actual)
+	}
+
+	// Statistics are saved in background, or by Save.
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Should not be saved synchronously, but got %v", err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Statistics are loaded from the file.
+	loaded := &Frecency{Path: path, HalfLife: time.Hour, now: clock}
+	if actual := suggestTexts(loaded.Rank(staticCompleter("develop", "feature", "main", "master"))(newDocument("git checkout "))); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+	expected = []string{"master", "develop", "feature", "main"}
+	if actual := suggestTexts(loaded.Rank(staticCompleter("develop", "feature", "main", "master"))(newDocument("git push origin "))); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestFrecencyMaxEntries(t *testing.T) {
+	now := time.Now()
+	f := &Frecency{MaxEntries: 2, now: func() time.Time { return now }}
+	for _, text := range []string{"a", "a", "b", "c"} {
+		now = now.Add(time.Minute)
+		f.Record(newDocument("cmd "), prompt.Suggest{Text: text})
+	}
+	d := newDocument("cmd ")
+	if f.Score(d, "a") == 0 || f.Score(d, "c") == 0 || f.Score(d, "b") != 0 {
+		t.Errorf("Should drop the lowest scored one, but got a=%v b=%v c=%v", f.Score(d, "a"), f.Score(d, "b"), f.Score(d, "c"))
+	}
+}
+
+func TestFrecencySaveError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-prompt-frecency")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The directory of Path does not exist yet.
+	path := filepath.Join(dir, "missing", "frecency.json")
+	f := &Frecency{Path: path}
+	f.Record(newDocument("git checkout "), prompt.Suggest{Text: "main"})
+	if err := f.Save(); err == nil {
+		t.Error("Should return an error if the file cannot be written")
+	}
+
+	// Statistics are saved next time.
+	if err := os.Mkdir(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Should be saved, but got %v", err)
+	}
+}
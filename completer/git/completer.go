@@ -0,0 +1,177 @@
+// Package git provides completers for git refs, remotes and changed files.
+// They read the repository on the local disk without running the git command.
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// Completer completes git objects in the repository enclosing Dir.
+// Each method can be used as prompt.Completer like `prompt.New(executor, c.Branches)`.
+type Completer struct {
+	// Dir is a directory in the repository. The current working directory is used if empty.
+	Dir        string
+	IgnoreCase bool
+}
+
+func (c *Completer) open() *Repository {
+	dir := c.Dir
+	if dir == "" {
+		dir = "."
+	}
+	r, err := Open(dir)
+	if err != nil {
+		if err != ErrNotRepository {
+			debug.Log("completer/git: cannot open repository:" + err.Error())
+		}
+		return nil
+	}
+	return r
+}
+
+// Branches returns local branches with the subject of the commit.
+// The current branch comes first.
+func (c *Completer) Branches(d prompt.Document) []prompt.Suggest {
+	return c.refs(d, "refs/heads/")
+}
+
+// Tags returns tags with the subject of the annotated tag or the commit.
+func (c *Completer) Tags(d prompt.Document) []prompt.Suggest {
+	return c.refs(d, "refs/tags/")
+}
+
+// RemoteBranches returns remote-tracking branches like "origin/main" with the subject of the commit.
+func (c *Completer) RemoteBranches(d prompt.Document) []prompt.Suggest {
+	return c.refs(d, "refs/remotes/")
+}
+
+// Refs returns local branches, tags and remote-tracking branches.
+func (c *Completer) Refs(d prompt.Document) []prompt.Suggest {
+	return c.refs(d, "refs/heads/", "refs/tags/", "refs/remotes/")
+}
+
+func (c *Completer) refs(d prompt.Document, prefixes ...string) []prompt.Suggest {
+	r := c.open()
+	if r == nil {
+		return nil
+	}
+	head, err := r.Head()
+	if err != nil {
+		debug.Log("completer/git: cannot read HEAD:" + err.Error())
+	}
+
+	var suggests []prompt.Suggest
+	var hashes []string
+	for _, prefix := range prefixes {
+		refs, err := r.Refs(prefix)
+		if err != nil {
+			debug.Log("completer/git: cannot read refs:" + err.Error())
+			return nil
+		}
+		for _, ref := range refs {
+			s := prompt.Suggest{Text: ref.ShortName()}
+			if ref.Name == head {
+				// Put the current branch first.
+				suggests = append([]prompt.Suggest{s}, suggests...)
+				hashes = append([]string{ref.Hash}, hashes...)
+				continue
+			}
+			suggests = append(suggests, s)
+			hashes = append(hashes, ref.Hash)
+		}
+	}
+
+	// Read commits only for the filtered suggestions since it is slow for many refs.
+	word := d.GetWordBeforeCursor()
+	if c.IgnoreCase {
+		word = strings.ToUpper(word)
+	}
+	ret := make([]prompt.Suggest, 0, len(suggests))
+	for i := range suggests {
+		text := suggests[i].Text
+		if c.IgnoreCase {
+			text = strings.ToUpper(text)
+		}
+		if !strings.HasPrefix(text, word) {
+			continue
+		}
+		subject, err := r.Subject(hashes[i])
+		if err != nil {
+			debug.Log("completer/git: cannot read object:" + err.Error())
+		}
+		suggests[i].Description = subject
+		ret = append(ret, suggests[i])
+	}
+	return ret
+}
+
+// Remotes returns remotes with their URLs.
+func (c *Completer) Remotes(d prompt.Document) []prompt.Suggest {
+	r := c.open()
+	if r == nil {
+		return nil
+	}
+	remotes, err := r.Remotes()
+	if err != nil {
+		debug.Log("completer/git: cannot read config:" + err.Error())
+		return nil
+	}
+	suggests := make([]prompt.Suggest, 0, len(remotes))
+	for _, remote := range remotes {
+		suggests = append(suggests, prompt.Suggest{Text: remote.Name, Description: remote.URL})
+	}
+	return prompt.FilterHasPrefix(suggests, d.GetWordBeforeCursor(), c.IgnoreCase)
+}
+
+// ChangedFiles returns files changed in the working tree, the most recently changed first.
+// The paths are relative to Dir like the arguments of `git add`.
+func (c *Completer) ChangedFiles(d prompt.Document) []prompt.Suggest {
+	r := c.open()
+	if r == nil {
+		return nil
+	}
+	changed, err := r.ChangedFiles()
+	if err != nil {
+		debug.Log("completer/git: cannot read index:" + err.Error())
+		return nil
+	}
+	dir := c.Dir
+	if dir == "" {
+		if dir, err = os.Getwd(); err != nil {
+			return nil
+		}
+	}
+
+	suggests := make([]prompt.Suggest, 0, len(changed))
+	for _, f := range changed {
+		path, err := relativePath(r.WorkTree, dir, f.Path)
+		if err != nil {
+			continue
+		}
+		description := "modified " + f.ModTime.Format(time.Stamp)
+		if f.Deleted {
+			description = "deleted"
+		}
+		suggests = append(suggests, prompt.Suggest{Text: path, Description: description})
+	}
+	return prompt.FilterHasPrefix(suggests, d.GetWordBeforeCursor(), c.IgnoreCase)
+}
+
+// relativePath converts a path in the index to the one relative to dir.
+func relativePath(workTree, dir, path string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(dir, filepath.Join(workTree, filepath.FromSlash(path)))
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
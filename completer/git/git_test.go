@@ -0,0 +1,203 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// newTestRepository creates a repository by the git command to test reading it without the git command.
+func newTestRepository(t *testing.T) (string, func(args ...string)) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	dir, err := ioutil.TempDir("", "go-prompt-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir, err = filepath.EvalSymlinks(dir); err != nil {
+		t.Fatal(err)
+	}
+	git := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GIT_CONFIG_NOSYSTEM=1", "HOME="+dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git("init", "-q", "-b", "main")
+	write("README.md", "# test\n")
+	write("src/main.go", "package main\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "Initial commit\n\nwith a body")
+	git("tag", "v0.1.0")
+	git("checkout", "-q", "-b", "feature/foo")
+	write("src/main.go", "package main\n\nfunc main() {}\n")
+	git("commit", "-q", "-am", "Add main function")
+	git("tag", "-a", "v0.2.0", "-m", "Release v0.2.0")
+	git("remote", "add", "origin", "https://example.com/origin.git")
+	git("update-ref", "refs/remotes/origin/main", "main")
+	return dir, git
+}
+
+func complete(f prompt.Completer, text string) []prompt.Suggest {
+	buf := prompt.NewBuffer()
+	buf.InsertText(text, false, true)
+	return f(*buf.Document())
+}
+
+func TestCompleter(t *testing.T) {
+	dir, git := newTestRepository(t)
+	defer os.RemoveAll(dir)
+	c := &Completer{Dir: filepath.Join(dir, "src")}
+
+	check := func(name string) {
+		scenarioTable := []struct {
+			completer prompt.Completer
+			text      string
+			expected  []prompt.Suggest
+		}{
+			{
+				completer: c.Branches,
+				text:      "git checkout ",
+				expected: []prompt.Suggest{
+					{Text: "feature/foo", Description: "Add main function"},
+					{Text: "main", Description: "Initial commit"},
+				},
+			},
+			{
+				completer: c.Tags,
+				text:      "git checkout v",
+				expected: []prompt.Suggest{
+					{Text: "v0.1.0", Description: "Initial commit"},
+					{Text: "v0.2.0", Description: "Release v0.2.0"},
+				},
+			},
+			{
+				completer: c.Refs,
+				text:      "git checkout ma",
+				expected: []prompt.Suggest{
+					{Text: "main", Description: "Initial commit"},
+				},
+			},
+			{
+				completer: c.RemoteBranches,
+				text:      "git merge ",
+				expected: []prompt.Suggest{
+					{Text: "origin/main", Description: "Initial commit"},
+				},
+			},
+			{
+				completer: c.Remotes,
+				text:      "git push ",
+				expected: []prompt.Suggest{
+					{Text: "origin", Description: "https://example.com/origin.git"},
+				},
+			},
+		}
+		for i, s := range scenarioTable {
+			if actual := complete(s.completer, s.text); !reflect.DeepEqual(actual, s.expected) {
+				t.Errorf("[%s %d] Should be %#v, but got %#v", name, i, s.expected, actual)
+			}
+		}
+	}
+
+	check("loose")
+	// Move objects and refs into pack files and packed-refs.
+	git("gc", "-q", "--aggressive")
+	check("packed")
+}
+
+func TestCompleterOutsideRepository(t *testing.T) {
+	dir, err := ioutil.TempDir("", "go-prompt-git")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := &Completer{Dir: dir}
+	if actual := complete(c.Branches, "git checkout "); actual != nil {
+		t.Errorf("Should be nil, but got %#v", actual)
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	dir, git := newTestRepository(t)
+	defer os.RemoveAll(dir)
+
+	later := time.Now().Add(time.Hour)
+	if err := ioutil.WriteFile(filepath.Join(dir, "src", "main.go"), []byte("package main // changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(filepath.Join(dir, "src", "main.go"), later, later); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "README.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, version := range []string{"2", "3", "4"} {
+		git("update-index", "--index-version", version)
+		c := &Completer{Dir: filepath.Join(dir, "src")}
+		expected := []prompt.Suggest{
+			{Text: "main.go", Description: "modified " + later.Format(time.Stamp)},
+			{Text: "../README.md", Description: "deleted"},
+		}
+		if actual := complete(c.ChangedFiles, "git add "); !reflect.DeepEqual(actual, expected) {
+			t.Errorf("[v%s] Should be %#v, but got %#v", version, expected, actual)
+		}
+	}
+}
+
+func TestWorktree(t *testing.T) {
+	dir, git := newTestRepository(t)
+	defer os.RemoveAll(dir)
+	git("worktree", "add", "-q", "-b", "hotfix", filepath.Join(dir, "worktree"), "main")
+
+	c := &Completer{Dir: filepath.Join(dir, "worktree")}
+	expected := []prompt.Suggest{
+		{Text: "hotfix", Description: "Initial commit"},
+		{Text: "feature/foo", Description: "Add main function"},
+		{Text: "main", Description: "Initial commit"},
+	}
+	if actual := complete(c.Branches, "git checkout "); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+}
+
+func TestApplyDelta(t *testing.T) {
+	base := []byte("hello, world")
+	delta := []byte{
+		12, 14, // the size of the base and the result
+		0x80 | 0x01 | 0x10, 7, 5, // copy "world" from the offset 7
+		0x02, '!', '!', // insert "!!"
+		0x80 | 0x10, 7, // copy "hello, " from the offset 0
+	}
+	actual, err := applyDelta(base, delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != "world!!hello, " {
+		t.Errorf("Should be %q, but got %q", "world!!hello, ", actual)
+	}
+
+	if _, err := applyDelta(base, []byte{12, 1, 0x80 | 0x01 | 0x10, 12, 1}); err == nil {
+		t.Errorf("Should be an error for copying out of the base")
+	}
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// IndexEntry is a file staged in the index.
+type IndexEntry struct {
+	// Path is the slash separated path from the top of the working tree.
+	Path    string
+	ModTime time.Time
+	Size    uint32
+}
+
+// Index returns the entries of the index (version 2, 3 or 4), sorted by path.
+// See https://git-scm.com/docs/index-format for the format.
+func (r *Repository) Index() ([]IndexEntry, error) {
+	b, err := ioutil.ReadFile(filepath.Join(r.GitDir, "index"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if len(b) < 12 || !bytes.Equal(b[:4], []byte("DIRC")) {
+		return nil, fmt.Errorf("git: broken index")
+	}
+	version := binary.BigEndian.Uint32(b[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf("git: unsupported index version %d", version)
+	}
+	count := int(binary.BigEndian.Uint32(b[8:12]))
+
+	entries := make([]IndexEntry, 0, count)
+	var previous []byte
+	data := b[12:]
+	for i := 0; i < count; i++ {
+		// ctime, mtime, dev, ino, mode, uid, gid, size, object name and flags.
+		const fixedSize = 40 + 20 + 2
+		if len(data) < fixedSize {
+			return nil, fmt.Errorf("git: broken index")
+		}
+		e := IndexEntry{
+			ModTime: time.Unix(int64(binary.BigEndian.Uint32(data[8:12])), int64(binary.BigEndian.Uint32(data[12:16]))),
+			Size:    binary.BigEndian.Uint32(data[36:40]),
+		}
+		flags := binary.BigEndian.Uint16(data[60:62])
+		n := fixedSize
+		if version >= 3 && flags&0x4000 != 0 {
+			n += 2 // Extended flags
+		}
+
+		var name []byte
+		if version == 4 {
+			// The path is compressed as the number of bytes removed from the previous path and a suffix.
+			strip, m := readOffset(data[n:])
+			if m <= 0 || int(strip) > len(previous) {
+				return nil, fmt.Errorf("git: broken index")
+			}
+			end := bytes.IndexByte(data[n+m:], 0)
+			if end < 0 {
+				return nil, fmt.Errorf("git: broken index")
+			}
+			name = append(append([]byte{}, previous[:len(previous)-int(strip)]...), data[n+m:n+m+end]...)
+			n += m + end + 1
+		} else {
+			end := bytes.IndexByte(data[n:], 0)
+			if end < 0 {
+				return nil, fmt.Errorf("git: broken index")
+			}
+			name = data[n : n+end]
+			// Entries are padded with NULs to a multiple of 8 bytes.
+			n = (n + end + 8) &^ 7
+		}
+		if n > len(data) {
+			return nil, fmt.Errorf("git: broken index")
+		}
+		e.Path = string(name)
+		entries = append(entries, e)
+		previous = name
+		data = data[n:]
+	}
+	return entries, nil
+}
+
+// readOffset reads a variable length integer encoded like the offset of OFS_DELTA.
+func readOffset(b []byte) (uint64, int) {
+	var v uint64
+	for i, c := range b {
+		v = v<<7 | uint64(c&0x7f)
+		if c&0x80 == 0 {
+			return v, i + 1
+		}
+		v++
+	}
+	return 0, 0
+}
+
+// ChangedFile is a file in the working tree which differs from the index.
+type ChangedFile struct {
+	Path    string
+	ModTime time.Time
+	Deleted bool
+}
+
+// ChangedFiles returns the files whose modification time or size differs from the index,
+// with the most recently changed one first. Untracked files are not included.
+func (r *Repository) ChangedFiles() ([]ChangedFile, error) {
+	entries, err := r.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []ChangedFile
+	for _, e := range entries {
+		fi, err := os.Lstat(filepath.Join(r.WorkTree, filepath.FromSlash(e.Path)))
+		if os.IsNotExist(err) {
+			changed = append(changed, ChangedFile{Path: e.Path, ModTime: e.ModTime, Deleted: true})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		if fi.ModTime().Unix() != e.ModTime.Unix() || uint32(fi.Size()) != e.Size {
+			changed = append(changed, ChangedFile{Path: e.Path, ModTime: fi.ModTime()})
+		}
+	}
+	sort.SliceStable(changed, func(i, j int) bool {
+		return changed[i].ModTime.After(changed[j].ModTime)
+	})
+	return changed, nil
+}
@@ -0,0 +1,335 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Object types stored in pack files.
+const (
+	objCommit   = 1
+	objTree     = 2
+	objBlob     = 3
+	objTag      = 4
+	objOfsDelta = 6
+	objRefDelta = 7
+)
+
+var objTypes = map[string]int{"commit": objCommit, "tree": objTree, "blob": objBlob, "tag": objTag}
+
+// maxDeltaDepth prevents infinite loops in broken pack files.
+const maxDeltaDepth = 64
+
+// errObjectNotFound is returned when the object is neither loose nor in the pack files.
+var errObjectNotFound = errors.New("git: object not found")
+
+// Subject returns the first line of the message of a commit or an annotated tag.
+// If an annotated tag has an empty message, the subject of the tagged object is returned.
+func (r *Repository) Subject(hash string) (string, error) {
+	for depth := 0; depth < maxDeltaDepth; depth++ {
+		typ, data, err := r.readObject(hash)
+		if err != nil {
+			return "", err
+		}
+		if typ != objCommit && typ != objTag {
+			return "", nil
+		}
+		headers := data
+		var message []byte
+		if i := bytes.Index(data, []byte("\n\n")); i >= 0 {
+			headers, message = data[:i], data[i+2:]
+		}
+		if subject := strings.TrimSpace(strings.SplitN(string(message), "\n", 2)[0]); subject != "" || typ == objCommit {
+			return subject, nil
+		}
+		// Follow the tagged object, which is in the header like "object <hash>".
+		hash = ""
+		for _, line := range strings.Split(string(headers), "\n") {
+			if strings.HasPrefix(line, "object ") {
+				hash = line[len("object "):]
+				break
+			}
+		}
+		if hash == "" {
+			return "", nil
+		}
+	}
+	return "", nil
+}
+
+// readObject returns the type and the content of the object.
+func (r *Repository) readObject(hash string) (int, []byte, error) {
+	return r.readObjectAt(hash, 0)
+}
+
+// readObjectAt is readObject for a base object at depth in a delta chain.
+func (r *Repository) readObjectAt(hash string, depth int) (int, []byte, error) {
+	typ, data, err := r.readLooseObject(hash)
+	if err != errObjectNotFound {
+		return typ, data, err
+	}
+	return r.readPackedObject(hash, depth)
+}
+
+func (r *Repository) readLooseObject(hash string) (int, []byte, error) {
+	if len(hash) < 3 {
+		return 0, nil, errObjectNotFound
+	}
+	f, err := os.Open(filepath.Join(r.CommonDir, "objects", hash[:2], hash[2:]))
+	if os.IsNotExist(err) {
+		return 0, nil, errObjectNotFound
+	} else if err != nil {
+		return 0, nil, err
+	}
+	defer f.Close()
+
+	zr, err := zlib.NewReader(f)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer zr.Close()
+	b, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// A loose object starts with a header like "commit 123\x00".
+	i := bytes.IndexByte(b, 0)
+	if i < 0 {
+		return 0, nil, fmt.Errorf("git: broken object %s", hash)
+	}
+	header := strings.SplitN(string(b[:i]), " ", 2)
+	typ, ok := objTypes[header[0]]
+	if !ok {
+		return 0, nil, fmt.Errorf("git: unknown object type %q", header[0])
+	}
+	return typ, b[i+1:], nil
+}
+
+func (r *Repository) readPackedObject(hash string, depth int) (int, []byte, error) {
+	if depth > maxDeltaDepth {
+		return 0, nil, fmt.Errorf("git: too deep delta chain")
+	}
+	id, err := hex.DecodeString(hash)
+	if err != nil || len(id) != 20 {
+		return 0, nil, errObjectNotFound
+	}
+	idxFiles, err := filepath.Glob(filepath.Join(r.CommonDir, "objects", "pack", "pack-*.idx"))
+	if err != nil {
+		return 0, nil, err
+	}
+	for _, idxFile := range idxFiles {
+		offset, err := findPackOffset(idxFile, id)
+		if err == errObjectNotFound {
+			continue
+		} else if err != nil {
+			return 0, nil, err
+		}
+		return r.readPackEntry(strings.TrimSuffix(idxFile, ".idx")+".pack", offset, depth)
+	}
+	return 0, nil, errObjectNotFound
+}
+
+// findPackOffset looks up the offset of the object in the pack index (version 2) by binary search.
+// See https://git-scm.com/docs/pack-format for the format.
+func findPackOffset(idxFile string, id []byte) (int64, error) {
+	f, err := os.Open(idxFile)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	header := make([]byte, 8+256*4)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return 0, err
+	}
+	if !bytes.Equal(header[:4], []byte("\377tOc")) || binary.BigEndian.Uint32(header[4:8]) != 2 {
+		return 0, fmt.Errorf("git: unsupported pack index %s", idxFile)
+	}
+	fanout := header[8:]
+	total := int64(binary.BigEndian.Uint32(fanout[255*4:]))
+	lo := int64(0)
+	if id[0] > 0 {
+		lo = int64(binary.BigEndian.Uint32(fanout[(int(id[0])-1)*4:]))
+	}
+	hi := int64(binary.BigEndian.Uint32(fanout[int(id[0])*4:]))
+
+	namesOffset := int64(len(header))
+	name := make([]byte, 20)
+	for lo < hi {
+		mid := (lo + hi) / 2
+		if _, err := f.ReadAt(name, namesOffset+mid*20); err != nil {
+			return 0, err
+		}
+		switch c := bytes.Compare(name, id); {
+		case c < 0:
+			lo = mid + 1
+		case c > 0:
+			hi = mid
+		default:
+			// Names are followed by CRC32s, 4-byte offsets and 8-byte offsets for large packs.
+			offsetsOffset := namesOffset + total*20 + total*4
+			b := make([]byte, 8)
+			if _, err := f.ReadAt(b[:4], offsetsOffset+mid*4); err != nil {
+				return 0, err
+			}
+			offset := binary.BigEndian.Uint32(b[:4])
+			if offset&0x80000000 == 0 {
+				return int64(offset), nil
+			}
+			if _, err := f.ReadAt(b, offsetsOffset+total*4+int64(offset&0x7fffffff)*8); err != nil {
+				return 0, err
+			}
+			return int64(binary.BigEndian.Uint64(b)), nil
+		}
+	}
+	return 0, errObjectNotFound
+}
+
+func (r *Repository) readPackEntry(packFile string, offset int64, depth int) (int, []byte, error) {
+	f, err := os.Open(packFile)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer f.Close()
+	return r.readPackEntryAt(f, offset, depth)
+}
+
+func (r *Repository) readPackEntryAt(f *os.File, offset int64, depth int) (int, []byte, error) {
+	if depth > maxDeltaDepth {
+		return 0, nil, fmt.Errorf("git: too deep delta chain")
+	}
+	br := bufio.NewReader(io.NewSectionReader(f, offset, 1<<62))
+
+	// The header is a type and a size encoded like a varint.
+	c, err := br.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	typ := int(c>>4) & 7
+	for c&0x80 != 0 {
+		if c, err = br.ReadByte(); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	var baseType int
+	var base []byte
+	switch typ {
+	case objOfsDelta:
+		c, err := br.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		rel := int64(c & 0x7f)
+		for c&0x80 != 0 {
+			if c, err = br.ReadByte(); err != nil {
+				return 0, nil, err
+			}
+			rel = ((rel + 1) << 7) | int64(c&0x7f)
+		}
+		if baseType, base, err = r.readPackEntryAt(f, offset-rel, depth+1); err != nil {
+			return 0, nil, err
+		}
+	case objRefDelta:
+		id := make([]byte, 20)
+		if _, err := io.ReadFull(br, id); err != nil {
+			return 0, nil, err
+		}
+		if baseType, base, err = r.readObjectAt(hex.EncodeToString(id), depth+1); err != nil {
+			return 0, nil, err
+		}
+	}
+
+	zr, err := zlib.NewReader(br)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer zr.Close()
+	data, err := ioutil.ReadAll(zr)
+	if err != nil {
+		return 0, nil, err
+	}
+	if typ != objOfsDelta && typ != objRefDelta {
+		return typ, data, nil
+	}
+	data, err = applyDelta(base, data)
+	return baseType, data, err
+}
+
+// applyDelta reconstructs an object from the base object and the delta instructions.
+func applyDelta(base, delta []byte) ([]byte, error) {
+	errBroken := errors.New("git: broken delta")
+	readSize := func() (uint64, error) {
+		v, n := binary.Uvarint(delta)
+		if n <= 0 {
+			return 0, errBroken
+		}
+		delta = delta[n:]
+		return v, nil
+	}
+	baseSize, err := readSize()
+	if err != nil {
+		return nil, err
+	}
+	if baseSize != uint64(len(base)) {
+		return nil, errBroken
+	}
+	size, err := readSize()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]byte, 0, size)
+	for len(delta) > 0 {
+		op := delta[0]
+		delta = delta[1:]
+		if op&0x80 == 0 {
+			// Insert the following op bytes.
+			if op == 0 || int(op) > len(delta) {
+				return nil, errBroken
+			}
+			ret = append(ret, delta[:op]...)
+			delta = delta[op:]
+			continue
+		}
+
+		// Copy from the base. The bits of op tell which bytes of the offset and the size follow.
+		var offset, n uint64
+		for i := uint(0); i < 7; i++ {
+			if op&(1<<i) == 0 {
+				continue
+			}
+			if len(delta) == 0 {
+				return nil, errBroken
+			}
+			if i < 4 {
+				offset |= uint64(delta[0]) << (8 * i)
+			} else {
+				n |= uint64(delta[0]) << (8 * (i - 4))
+			}
+			delta = delta[1:]
+		}
+		if n == 0 {
+			n = 0x10000
+		}
+		if offset+n > uint64(len(base)) {
+			return nil, errBroken
+		}
+		ret = append(ret, base[offset:offset+n]...)
+	}
+	if uint64(len(ret)) != size {
+		return nil, errBroken
+	}
+	return ret, nil
+}
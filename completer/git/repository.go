@@ -0,0 +1,243 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotRepository is returned when the directory is not in a git repository.
+var ErrNotRepository = errors.New("git: not a git repository")
+
+// Repository is a git repository on the local disk.
+type Repository struct {
+	// WorkTree is the top level directory of the working tree.
+	WorkTree string
+	// GitDir is the ".git" directory. It differs from CommonDir in linked worktrees.
+	GitDir string
+	// CommonDir is the directory containing refs and objects shared by worktrees.
+	CommonDir string
+}
+
+// Ref is a reference like a branch or a tag.
+type Ref struct {
+	// Name is the full name like "refs/heads/main".
+	Name string
+	// Hash is the hex object name which the ref points to.
+	Hash string
+}
+
+// ShortName returns the name without "refs/heads/", "refs/tags/" or "refs/remotes/".
+func (r Ref) ShortName() string {
+	for _, prefix := range []string{"refs/heads/", "refs/tags/", "refs/remotes/"} {
+		if strings.HasPrefix(r.Name, prefix) {
+			return r.Name[len(prefix):]
+		}
+	}
+	return r.Name
+}
+
+// Open returns the repository enclosing dir by looking for ".git" in dir and its parents.
+func Open(dir string) (*Repository, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		gitDir, err := findGitDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		if gitDir != "" {
+			return newRepository(dir, gitDir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, ErrNotRepository
+		}
+		dir = parent
+	}
+}
+
+// findGitDir returns the git directory of the working tree dir, or "" if dir is not a working tree.
+func findGitDir(dir string) (string, error) {
+	path := filepath.Join(dir, ".git")
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	if fi.IsDir() {
+		return path, nil
+	}
+
+	// ".git" is a file like "gitdir: ../.git/worktrees/foo" in linked worktrees and submodules.
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	s := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(s, "gitdir:") {
+		return "", ErrNotRepository
+	}
+	gitDir := strings.TrimSpace(s[len("gitdir:"):])
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(dir, gitDir)
+	}
+	return gitDir, nil
+}
+
+func newRepository(workTree, gitDir string) (*Repository, error) {
+	r := &Repository{WorkTree: workTree, GitDir: gitDir, CommonDir: gitDir}
+	b, err := ioutil.ReadFile(filepath.Join(gitDir, "commondir"))
+	if err == nil {
+		r.CommonDir = strings.TrimSpace(string(b))
+		if !filepath.IsAbs(r.CommonDir) {
+			r.CommonDir = filepath.Join(gitDir, r.CommonDir)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Head returns the ref name like "refs/heads/main" if HEAD points to a branch,
+// otherwise the hex object name of the detached HEAD.
+func (r *Repository) Head() (string, error) {
+	b, err := ioutil.ReadFile(filepath.Join(r.GitDir, "HEAD"))
+	if err != nil {
+		return "", err
+	}
+	s := strings.TrimSpace(string(b))
+	return strings.TrimPrefix(s, "ref: "), nil
+}
+
+// Refs returns the refs whose name starts with prefix like "refs/heads/", sorted by name.
+// Symbolic refs like "refs/remotes/origin/HEAD" are skipped.
+func (r *Repository) Refs(prefix string) ([]Ref, error) {
+	refs := make(map[string]string)
+	if err := r.readPackedRefs(prefix, refs); err != nil {
+		return nil, err
+	}
+	// Loose refs take precedence over packed ones.
+	root := filepath.Join(r.CommonDir, filepath.FromSlash(prefix))
+	err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		hash := strings.TrimSpace(string(b))
+		if !isHash(hash) {
+			return nil
+		}
+		rel, err := filepath.Rel(r.CommonDir, path)
+		if err != nil {
+			return err
+		}
+		refs[filepath.ToSlash(rel)] = hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]Ref, 0, len(refs))
+	for name, hash := range refs {
+		ret = append(ret, Ref{Name: name, Hash: hash})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Name < ret[j].Name
+	})
+	return ret, nil
+}
+
+func (r *Repository) readPackedRefs(prefix string, refs map[string]string) error {
+	f, err := os.Open(filepath.Join(r.CommonDir, "packed-refs"))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Lines are like "<hash> refs/heads/main". "#" starts a header and "^" starts a peeled tag.
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") || strings.HasPrefix(line, "^") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 2 && isHash(fields[0]) && strings.HasPrefix(fields[1], prefix) {
+			refs[fields[1]] = fields[0]
+		}
+	}
+	return scanner.Err()
+}
+
+// Remote is a remote repository configured in .git/config.
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// Remotes returns the remotes in the order of .git/config.
+func (r *Repository) Remotes() ([]Remote, error) {
+	f, err := os.Open(filepath.Join(r.CommonDir, "config"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var remotes []Remote
+	var current *Remote
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			// A section like `[remote "origin"]`.
+			current = nil
+			section := strings.Trim(line, "[]")
+			if strings.HasPrefix(section, "remote ") {
+				remotes = append(remotes, Remote{Name: strings.Trim(section[len("remote "):], ` "`)})
+				current = &remotes[len(remotes)-1]
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if i := strings.IndexByte(line, '='); i > 0 && strings.EqualFold(strings.TrimSpace(line[:i]), "url") {
+			current.URL = strings.TrimSpace(line[i+1:])
+		}
+	}
+	return remotes, scanner.Err()
+}
+
+func isHash(s string) bool {
+	if len(s) != 40 && len(s) != 64 {
+		return false
+	}
+	for _, r := range s {
+		if !('0' <= r && r <= '9') && !('a' <= r && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,240 @@
+package completer
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// HelpCompleter is a completer for a third party command, which parses its help message.
+// It runs `<Name> <subcommands...> --help` once for each subcommand and caches flags and subcommands in it.
+// The help messages like cobra, Python's argparse and GNU getopt_long are supported.
+type HelpCompleter struct {
+	// Name is the command name like "kubectl".
+	Name string
+	// HelpArgs are appended to the command line to print the help message. It is "--help" if nil.
+	HelpArgs []string
+	// ManPage reads the man page like `man git-commit` instead of running the command.
+	ManPage bool
+	// Timeout is how long it waits for the help message. It is 5 seconds if 0.
+	Timeout    time.Duration
+	IgnoreCase bool
+
+	mu    sync.Mutex
+	cache map[string]*parsedHelp
+}
+
+type parsedHelp struct {
+	flags       []prompt.Suggest
+	subcommands []prompt.Suggest
+}
+
+// Complete returns flags if the word before the cursor starts with "-", otherwise subcommands.
+func (c *HelpCompleter) Complete(d prompt.Document) []prompt.Suggest {
+	args := d.ShellArgs()
+	i := d.ShellArgIndex()
+	if len(args) == 0 || i == 0 || args[0] != c.Name {
+		return nil
+	}
+
+	// Find the subcommand by arguments which are listed as subcommands.
+	var path []string
+	h := c.help(path)
+	for _, arg := range args[1:i] {
+		if strings.HasPrefix(arg, "-") || !hasSuggest(h.subcommands, arg) {
+			continue
+		}
+		path = append(path, arg)
+		h = c.help(path)
+	}
+
+	word, _ := d.ShellWordBeforeCursor()
+	if strings.HasPrefix(word, "-") {
+		return copySuggests(prompt.FilterHasPrefix(h.flags, word, c.IgnoreCase))
+	}
+	return copySuggests(prompt.FilterHasPrefix(h.subcommands, word, c.IgnoreCase))
+}
+
+func hasSuggest(suggests []prompt.Suggest, text string) bool {
+	for i := range suggests {
+		if suggests[i].Text == text {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *HelpCompleter) help(path []string) *parsedHelp {
+	key := strings.Join(path, " ")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if h, ok := c.cache[key]; ok {
+		return h
+	}
+	if c.cache == nil {
+		c.cache = make(map[string]*parsedHelp)
+	}
+
+	h := &parsedHelp{}
+	if text, err := c.readHelp(path); err != nil {
+		debug.Log("completer: cannot read help message:" + err.Error())
+	} else {
+		h.flags, h.subcommands = ParseHelp(text)
+	}
+	// Cache it even if failed not to run the command on every keystroke.
+	c.cache[key] = h
+	return h
+}
+
+func (c *HelpCompleter) readHelp(path []string) (string, error) {
+	timeout := c.Timeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if c.ManPage {
+		cmd = exec.CommandContext(ctx, "man", strings.Join(append([]string{c.Name}, path...), "-"))
+		cmd.Env = append(os.Environ(), "MANPAGER=cat", "PAGER=cat", "MANWIDTH=200")
+	} else {
+		helpArgs := c.HelpArgs
+		if helpArgs == nil {
+			helpArgs = []string{"--help"}
+		}
+		cmd = exec.CommandContext(ctx, c.Name, append(append([]string{}, path...), helpArgs...)...)
+	}
+	// Some commands print the help message to stderr and exit with non-zero status.
+	out, err := cmd.CombinedOutput()
+	if len(out) == 0 && err != nil {
+		return "", err
+	}
+	return stripOverstrike(string(out)), nil
+}
+
+var overstrike = regexp.MustCompile(".\b")
+
+// stripOverstrike removes bold and underline like "N\bNA\bAM\bME" of man pages.
+func stripOverstrike(s string) string {
+	return overstrike.ReplaceAllString(s, "")
+}
+
+var (
+	flagName        = regexp.MustCompile(`^--?[A-Za-z0-9?][A-Za-z0-9_.-]*`)
+	subcommandName  = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.:-]*$`)
+	columnSeparator = regexp.MustCompile(`\s{2,}|\t`)
+)
+
+// ParseHelp parses flags and subcommands with their descriptions from a help message.
+// Flags are lines starting with "-" like "  -o, --output string   Output format".
+// Subcommands are lines in a section whose header contains "command" like "Available Commands:",
+// or the choices of argparse like "{add,remove}". Other positional arguments of argparse like "src"
+// are not subcommands.
+func ParseHelp(text string) (flags []prompt.Suggest, subcommands []prompt.Suggest) {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), " \t\r"))
+	}
+
+	seen := make(map[string]struct{})
+	add := func(suggests *[]prompt.Suggest, name, description string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		*suggests = append(*suggests, prompt.Suggest{Text: name, Description: description})
+	}
+
+	var inCommands, inPositional bool
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if indent == 0 {
+			// A section header like "Available Commands:", "positional arguments:" or "COMMANDS".
+			lower := strings.ToLower(trimmed)
+			inCommands = strings.Contains(lower, "command")
+			inPositional = strings.HasPrefix(lower, "positional arguments")
+			continue
+		}
+
+		spec, description := splitHelpColumns(trimmed)
+		if description == "" && i+1 < len(lines) {
+			description = nextLineDescription(lines[i+1], indent)
+		}
+
+		if strings.HasPrefix(trimmed, "-") {
+			for _, name := range parseFlagNames(spec) {
+				add(&flags, name, description)
+			}
+			continue
+		}
+		if !inCommands && !inPositional {
+			continue
+		}
+		if strings.HasPrefix(spec, "{") && strings.HasSuffix(spec, "}") {
+			// The choices of argparse subparsers like "{add,remove}", which are described in the following lines.
+			for _, name := range strings.Split(spec[1:len(spec)-1], ",") {
+				add(&subcommands, name, "")
+			}
+			continue
+		}
+		if name := strings.TrimSuffix(spec, ":"); subcommandName.MatchString(name) {
+			if _, ok := seen[name]; ok {
+				// Describe the choice of argparse.
+				for j := range subcommands {
+					if subcommands[j].Text == name && subcommands[j].Description == "" {
+						subcommands[j].Description = description
+					}
+				}
+				continue
+			}
+			if inCommands {
+				add(&subcommands, name, description)
+			}
+		}
+	}
+	return flags, subcommands
+}
+
+// splitHelpColumns splits a line like "-o, --output string   Output format" by 2 or more spaces.
+func splitHelpColumns(s string) (spec, description string) {
+	loc := columnSeparator.FindStringIndex(s)
+	if loc == nil {
+		return s, ""
+	}
+	return s[:loc[0]], strings.TrimSpace(s[loc[1]:])
+}
+
+// nextLineDescription returns the description written in the next line like argparse and man pages.
+func nextLineDescription(next string, indent int) string {
+	trimmed := strings.TrimSpace(next)
+	nextIndent := len(next) - len(strings.TrimLeft(next, " \t"))
+	if trimmed == "" || nextIndent <= indent || strings.HasPrefix(trimmed, "-") {
+		return ""
+	}
+	return trimmed
+}
+
+// parseFlagNames returns flag names in a spec like "-o OUTPUT, --output OUTPUT" or "--block-size=SIZE".
+func parseFlagNames(spec string) []string {
+	var names []string
+	for _, field := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == ' ' || r == '|' }) {
+		if name := flagName.FindString(field); name != "" && name != "-" && name != "--" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,183 @@
+package completer
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func TestParseHelp(t *testing.T) {
+	scenarioTable := []struct {
+		name        string
+		text        string
+		flags       []prompt.Suggest
+		subcommands []prompt.Suggest
+	}{
+		{
+			name: "cobra",
+			text: `kubectl controls the Kubernetes cluster manager.
+
+Usage:
+  kubectl [flags]
+  kubectl [command]
+
+Available Commands:
+  get         Display one or many resources
+  config      Modify kubeconfig files
+
+Flags:
+  -h, --help                help for kubectl
+  -n, --namespace string    If present, the namespace scope for this CLI request
+      --request-timeout string   The length of time to wait
+
+Use "kubectl [command] --help" for more information about a command.
+`,
+			flags: []prompt.Suggest{
+				{Text: "-h", Description: "help for kubectl"},
+				{Text: "--help", Description: "help for kubectl"},
+				{Text: "-n", Description: "If present, the namespace scope for this CLI request"},
+				{Text: "--namespace", Description: "If present, the namespace scope for this CLI request"},
+				{Text: "--request-timeout", Description: "The length of time to wait"},
+			},
+			subcommands: []prompt.Suggest{
+				{Text: "get", Description: "Display one or many resources"},
+				{Text: "config", Description: "Modify kubeconfig files"},
+			},
+		},
+		{
+			name: "argparse",
+			text: `usage: todo [-h] [-o OUTPUT] {add,remove} ...
+
+positional arguments:
+  {add,remove}          sub commands
+    add                 Add a task
+    remove              Remove a task
+
+options:
+  -h, --help            show this help message and exit
+  -o OUTPUT, --output OUTPUT
+                        output file
+`,
+			flags: []prompt.Suggest{
+				{Text: "-h", Description: "show this help message and exit"},
+				{Text: "--help", Description: "show this help message and exit"},
+				{Text: "-o", Description: "output file"},
+				{Text: "--output", Description: "output file"},
+			},
+			subcommands: []prompt.Suggest{
+				{Text: "add", Description: "Add a task"},
+				{Text: "remove", Description: "Remove a task"},
+			},
+		},
+		{
+			name: "argparse with positional arguments",
+			text: `usage: cp.py [-h] src dst
+
+positional arguments:
+  src         source file
+  dst         destination file
+
+options:
+  -h, --help  show this help message and exit
+`,
+			flags: []prompt.Suggest{
+				{Text: "-h", Description: "show this help message and exit"},
+				{Text: "--help", Description: "show this help message and exit"},
+			},
+			subcommands: nil,
+		},
+		{
+			name: "GNU",
+			text: `Usage: ls [OPTION]... [FILE]...
+List information about the FILEs (the current directory by default).
+
+  -a, --all                  do not ignore entries starting with .
+      --block-size=SIZE      with -l, scale sizes by SIZE when printing them;
+                               e.g., '--block-size=M'; see SIZE format below
+      --color[=WHEN]         color the output WHEN
+`,
+			flags: []prompt.Suggest{
+				{Text: "-a", Description: "do not ignore entries starting with ."},
+				{Text: "--all", Description: "do not ignore entries starting with ."},
+				{Text: "--block-size", Description: "with -l, scale sizes by SIZE when printing them;"},
+				{Text: "--color", Description: "color the output WHEN"},
+			},
+		},
+		{
+			name: "man page",
+			text: "N\bNA\bAM\bME\bE\n       ls - list directory contents\n\nOPTIONS\n" +
+				"       -\b-a\ba, -\b--\b-a\bal\bll\bl\n              do not ignore entries starting with .\n\n" +
+				"       -\b-A\bA\n              do not list implied . and ..\n",
+			flags: []prompt.Suggest{
+				{Text: "-a", Description: "do not ignore entries starting with ."},
+				{Text: "--all", Description: "do not ignore entries starting with ."},
+				{Text: "-A", Description: "do not list implied . and .."},
+			},
+		},
+	}
+
+	for _, s := range scenarioTable {
+		flags, subcommands := ParseHelp(stripOverstrike(s.text))
+		if !reflect.DeepEqual(flags, s.flags) {
+			t.Errorf("[%s] Should be %#v, but got %#v", s.name, s.flags, flags)
+		}
+		if !reflect.DeepEqual(subcommands, s.subcommands) {
+			t.Errorf("[%s] Should be %#v, but got %#v", s.name, s.subcommands, subcommands)
+		}
+	}
+}
+
+func TestHelpCompleter(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on windows")
+	}
+	dir, err := ioutil.TempDir("", "go-prompt-help")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The script counts how many times it is called to test the cache.
+	script := `#!/bin/sh
+echo called >> "$(dirname "$0")/calls"
+case "$1" in
+get) printf 'Flags:\n  -o, --output string   Output format\n' ;;
+*) printf 'Available Commands:\n  get    Display resources\n\nFlags:\n  -v, --verbose   Verbose output\n' ;;
+esac
+`
+	if err := ioutil.WriteFile(filepath.Join(dir, "mycli"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Setenv("PATH", os.Getenv("PATH"))
+	os.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	c := &HelpCompleter{Name: "mycli"}
+	scenarioTable := []struct {
+		text     string
+		expected []string
+	}{
+		{text: "mycli ", expected: []string{"get"}},
+		{text: "mycli --v", expected: []string{"--verbose"}},
+		{text: "mycli -v get -", expected: []string{"-o", "--output"}},
+		{text: "mycli get --o", expected: []string{"--output"}},
+		{text: "other ", expected: []string{}},
+	}
+	for _, s := range scenarioTable {
+		if actual := suggestTexts(c.Complete(newDocument(s.text))); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("%q: Should be %#v, but got %#v", s.text, s.expected, actual)
+		}
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "calls"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls := len(b) / len("called\n"); calls != 2 {
+		t.Errorf("Should be called once for each subcommand, but called %d times", calls)
+	}
+}
@@ -0,0 +1,231 @@
+package completer
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// errProcessTimeout is returned when the process doesn't respond in time.
+var errProcessTimeout = errors.New("completer: process timed out")
+
+// errProcessExited is returned when the process exits while completing.
+var errProcessExited = errors.New("completer: process exited")
+
+// lineProcess is a subprocess which reads requests from stdin and writes responses to stdout line by line.
+type lineProcess struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan string
+	done  chan struct{}
+}
+
+func startLineProcess(cmd *exec.Cmd) (*lineProcess, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	p := &lineProcess{cmd: cmd, stdin: stdin, lines: make(chan string, 64), done: make(chan struct{})}
+	go func() {
+		defer close(p.lines)
+		scanner := bufio.NewScanner(stdout)
+		scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			select {
+			case p.lines <- scanner.Text():
+			case <-p.done:
+				return
+			}
+		}
+	}()
+	return p, nil
+}
+
+// readLine returns the next line written by the process until the deadline.
+func (p *lineProcess) readLine(deadline <-chan time.Time) (string, error) {
+	select {
+	case l, ok := <-p.lines:
+		if !ok {
+			return "", errProcessExited
+		}
+		return l, nil
+	case <-deadline:
+		return "", errProcessTimeout
+	}
+}
+
+func (p *lineProcess) kill() {
+	close(p.done)
+	p.stdin.Close()
+	if p.cmd.Process != nil {
+		p.cmd.Process.Kill()
+	}
+	go p.cmd.Wait() // Reap the process without blocking the prompt.
+}
+
+// ProcessRequest is a line written to the stdin of the process for each completion.
+type ProcessRequest struct {
+	// ID is incremented for each request. The response must have the same ID.
+	ID int `json:"id"`
+	// Text is the whole input.
+	Text string `json:"text"`
+	// Cursor is the position of the cursor in runes.
+	Cursor int `json:"cursor"`
+	// Args are the arguments split like a POSIX shell.
+	Args []string `json:"args"`
+	// ArgIndex is the index of the argument under the cursor in Args.
+	ArgIndex int `json:"arg_index"`
+}
+
+// ProcessResponse is a line written to the stdout of the process for each request.
+type ProcessResponse struct {
+	ID          int                 `json:"id"`
+	Suggestions []ProcessSuggestion `json:"suggestions"`
+	// Error is logged and no suggestion is shown if not empty.
+	Error string `json:"error,omitempty"`
+}
+
+// ProcessSuggestion is a suggestion in ProcessResponse. See prompt.Suggest for the fields.
+type ProcessSuggestion struct {
+	Text        string        `json:"text"`
+	Description string        `json:"description,omitempty"`
+	DisplayText string        `json:"display_text,omitempty"`
+	Range       *ProcessRange `json:"range,omitempty"`
+}
+
+// ProcessRange is the range of text replaced by a suggestion relative to the cursor.
+type ProcessRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Process is a completer backed by a subprocess written in any language.
+// It writes a ProcessRequest as a line of JSON to the stdin of the process for each completion,
+// and reads a ProcessResponse as a line of JSON from its stdout.
+// For example, a request and a response are like:
+//
+//	{"id":1,"text":"git ch","cursor":6,"args":["git","ch"],"arg_index":1}
+//	{"id":1,"suggestions":[{"text":"checkout","description":"Switch branches"}]}
+//
+// The process is started on the first completion and reused.
+// If it exits or times out, it is restarted on the next completion. Call Close to stop it.
+type Process struct {
+	// Command and Args are the command line of the process.
+	Command string
+	Args    []string
+	// Dir and Env are passed to exec.Cmd.
+	Dir string
+	Env []string
+	// Timeout is how long it waits for a response. It is 1 second if 0.
+	Timeout time.Duration
+
+	mu   sync.Mutex
+	proc *lineProcess
+	seq  int
+}
+
+// Complete returns the suggestions in the response of the process.
+func (p *Process) Complete(d prompt.Document) []prompt.Suggest {
+	res, err := p.request(ProcessRequest{
+		Text:     d.Text,
+		Cursor:   len([]rune(d.TextBeforeCursor())),
+		Args:     d.ShellArgs(),
+		ArgIndex: d.ShellArgIndex(),
+	})
+	if err != nil {
+		debug.Log("completer: cannot complete by process:" + err.Error())
+		return nil
+	}
+	if res.Error != "" {
+		debug.Log("completer: process returned an error:" + res.Error)
+		return nil
+	}
+
+	suggests := make([]prompt.Suggest, 0, len(res.Suggestions))
+	for _, s := range res.Suggestions {
+		suggest := prompt.Suggest{Text: s.Text, Description: s.Description, DisplayText: s.DisplayText}
+		if s.Range != nil {
+			suggest.Range = &prompt.SuggestRange{Start: s.Range.Start, End: s.Range.End}
+		}
+		suggests = append(suggests, suggest)
+	}
+	return suggests
+}
+
+func (p *Process) request(req ProcessRequest) (*ProcessResponse, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.proc == nil {
+		cmd := exec.Command(p.Command, p.Args...)
+		cmd.Dir = p.Dir
+		cmd.Env = p.Env
+		proc, err := startLineProcess(cmd)
+		if err != nil {
+			return nil, err
+		}
+		p.proc = proc
+	}
+
+	p.seq++
+	req.ID = p.seq
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.proc.stdin.Write(append(b, '\n')); err != nil {
+		p.stop()
+		return nil, err
+	}
+
+	timeout := p.Timeout
+	if timeout == 0 {
+		timeout = time.Second
+	}
+	deadline := time.After(timeout)
+	for {
+		l, err := p.proc.readLine(deadline)
+		if err != nil {
+			p.stop()
+			return nil, err
+		}
+		var res ProcessResponse
+		if err := json.Unmarshal([]byte(l), &res); err != nil {
+			debug.Log("completer: invalid response from process:" + err.Error())
+			continue
+		}
+		if res.ID == req.ID {
+			return &res, nil
+		}
+		// Skip a late response to an older request.
+	}
+}
+
+func (p *Process) stop() {
+	if p.proc != nil {
+		p.proc.kill()
+		p.proc = nil
+	}
+}
+
+// Close stops the process. It is started again by the next completion.
+func (p *Process) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stop()
+	return nil
+}
@@ -0,0 +1,93 @@
+package completer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// TestHelperProcess is not a real test. It is run as the subprocess of Process in TestProcess.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_PROMPT_HELPER_PROCESS") != "1" {
+		return
+	}
+	defer os.Exit(0)
+
+	pid := os.Getpid()
+	scanner := bufio.NewScanner(os.Stdin)
+	for scanner.Scan() {
+		var req ProcessRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			fmt.Println("invalid")
+			continue
+		}
+		res := ProcessResponse{ID: req.ID}
+		switch {
+		case req.Text == "crash":
+			os.Exit(1)
+		case req.Text == "sleep":
+			time.Sleep(time.Second)
+		case req.Text == "error":
+			res.Error = "something wrong"
+		case req.Text == "pid":
+			res.Suggestions = []ProcessSuggestion{{Text: fmt.Sprint(pid)}}
+		default:
+			// Print garbage and a stale response first, which must be skipped.
+			fmt.Println("not json")
+			fmt.Printf("{\"id\":%d}\n", req.ID-1)
+			res.Suggestions = []ProcessSuggestion{
+				{Text: "args", Description: strings.Join(req.Args, ",")},
+				{Text: "cursor", Description: fmt.Sprintf("%d of %q at %d", req.Cursor, req.Text, req.ArgIndex), Range: &ProcessRange{Start: -1}},
+			}
+		}
+		b, _ := json.Marshal(res)
+		fmt.Println(string(b))
+	}
+}
+
+func TestProcess(t *testing.T) {
+	p := &Process{
+		Command: os.Args[0],
+		Args:    []string{"-test.run=TestHelperProcess"},
+		Env:     append(os.Environ(), "GO_PROMPT_HELPER_PROCESS=1"),
+		Timeout: 500 * time.Millisecond,
+	}
+	defer p.Close()
+
+	expected := []prompt.Suggest{
+		{Text: "args", Description: "git,my branch,-f"},
+		{Text: "cursor", Description: `15 of "git 'my branch' -f" at 1`, Range: &prompt.SuggestRange{Start: -1}},
+	}
+	buf := prompt.NewBuffer()
+	buf.InsertText("git 'my branch' -f", false, true)
+	buf.CursorLeft(3)
+	if actual := p.Complete(*buf.Document()); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+
+	pid := suggestTexts(p.Complete(newDocument("pid")))
+	if actual := suggestTexts(p.Complete(newDocument("pid"))); !reflect.DeepEqual(actual, pid) {
+		t.Errorf("Should reuse the process %#v, but got %#v", pid, actual)
+	}
+
+	for _, text := range []string{"error", "crash", "sleep"} {
+		if actual := p.Complete(newDocument(text)); actual != nil {
+			t.Errorf("%q: Should be nil, but got %#v", text, actual)
+		}
+		// The process is restarted after it crashed or timed out.
+		if actual := suggestTexts(p.Complete(newDocument("pid"))); len(actual) != 1 {
+			t.Errorf("%q: Should be restarted, but got %#v", text, actual)
+		} else if text != "error" && reflect.DeepEqual(actual, pid) {
+			t.Errorf("%q: Should be a new process, but got the same pid %#v", text, actual)
+		} else {
+			pid = actual
+		}
+	}
+}
@@ -0,0 +1,201 @@
+package completer
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// CompleteArg is the hidden argument to print suggestions for shell completion scripts.
+// The shell runs `<program> __complete <args...>` where the last argument is the one being completed.
+const CompleteArg = "__complete"
+
+// HandleCompleteArg prints suggestions of c and exits if the program is run like `<program> __complete <args...>`.
+// Call it at the beginning of main to power the completion scripts generated by
+// WriteBashCompletion, WriteZshCompletion and WriteFishCompletion by the same completer as the prompt.
+func HandleCompleteArg(c prompt.Completer) {
+	if len(os.Args) < 2 || os.Args[1] != CompleteArg {
+		return
+	}
+	if err := WriteCompletions(os.Stdout, filepath.Base(os.Args[0]), os.Args[2:], c); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// WriteCompletions writes suggestions for the last argument of args like "text\tdescription" line by line.
+// The document given to c is the command line of name and args, with the cursor at the end.
+// Each text is the whole argument after the suggestion is accepted, without shell quotes.
+func WriteCompletions(w io.Writer, name string, args []string, c prompt.Completer) error {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	words := make([]string, 0, len(args)+1)
+	for _, a := range append([]string{name}, args...) {
+		words = append(words, prompt.QuoteShellWord(a, prompt.ShellQuoteNone))
+	}
+	text := strings.Join(words, " ")
+	buf := prompt.NewBuffer()
+	buf.InsertText(text, false, true)
+	d := buf.Document()
+
+	for _, s := range c(*d) {
+		arg := acceptedArg(d, s)
+		if arg == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%s\t%s\n", arg, strings.Replace(s.Description, "\n", " ", -1)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// acceptedArg returns the argument under the cursor after s is inserted in place of the shell word before the cursor.
+// Text is quoted like the word as CompletionManager does with shell quoting unless Range is set.
+func acceptedArg(d *prompt.Document, s prompt.Suggest) string {
+	before := d.TextBeforeCursor()
+	var text string
+	if s.Range != nil {
+		runes := []rune(before)
+		n := -s.Range.Start
+		if n > len(runes) {
+			n = len(runes)
+		}
+		text = string(runes[:len(runes)-n]) + s.Text
+	} else {
+		_, quote := d.ShellWordBeforeCursor()
+		text = before[:d.FindStartOfPreviousShellWord()] + prompt.QuoteShellWord(s.Text, quote)
+	}
+	text += strings.TrimRight(s.AppendText, " ")
+
+	buf := prompt.NewBuffer()
+	buf.InsertText(text, false, true)
+	args := buf.Document().ShellArgs()
+	if i := d.ShellArgIndex(); i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+var nonIdentifier = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+var bashCompletion = template.Must(template.New("bash").Parse(`# bash completion for {{.Name}}
+# _{{.Func}}_dequote removes quotes and backslash escapes from a word without evaluating it.
+_{{.Func}}_dequote() {
+	local word=$1 out= quote= c i
+	for ((i = 0; i < ${#word}; i++)); do
+		c=${word:i:1}
+		if [[ $quote == "'" ]]; then
+			if [[ $c == "'" ]]; then quote=; else out+=$c; fi
+		elif [[ $c == '\' ]] && [[ -z $quote || '$"\' == *"${word:i+1:1}"* ]]; then
+			((i++))
+			out+=${word:i:1}
+		elif [[ $quote == '"' ]]; then
+			if [[ $c == '"' ]]; then quote=; else out+=$c; fi
+		elif [[ $c == "'" || $c == '"' ]]; then
+			quote=$c
+		else
+			out+=$c
+		fi
+	done
+	printf '%s' "$out"
+}
+
+# COMP_WORDS is also split at COMP_WORDBREAKS like "=" and ":", so the words not separated
+# by white spaces in COMP_LINE are joined. Readline replaces only the text after the last
+# word break, so it is removed from the beginning of the replies.
+_{{.Func}}_complete() {
+	local line word reply prefix= rest=${COMP_LINE:0:COMP_POINT} trimmed i
+	local breaks=${COMP_WORDBREAKS//[[:space:]\"\']/}
+	local -a words args
+	for ((i = 0; i <= COMP_CWORD; i++)); do
+		word=${COMP_WORDS[i]}
+		trimmed=${rest#"${rest%%[![:space:]]*}"}
+		if ((i > 1)) && [[ $trimmed == "$rest" ]]; then
+			words[${#words[@]}-1]+=$word
+		else
+			words+=("$word")
+		fi
+		rest=${trimmed#"$word"}
+	done
+	word=${words[${#words[@]}-1]}
+	for ((i = ${#word} - 1; i >= 0; i--)); do
+		if [[ $breaks == *"${word:i:1}"* ]]; then
+			prefix=${word:0:i+1}
+			break
+		fi
+	done
+	for word in "${words[@]:1}"; do
+		args+=("$(_{{.Func}}_dequote "$word")")
+	done
+	COMPREPLY=()
+	while IFS= read -r line; do
+		reply=$(printf '%q' "${line%%$'\t'*}")
+		COMPREPLY+=("${reply#"$prefix"}")
+	done < <({{.Name}} {{.CompleteArg}} "${args[@]}" 2>/dev/null)
+}
+complete -o default -F _{{.Func}}_complete {{.Name}}
+`))
+
+var zshCompletion = template.Must(template.New("zsh").Parse(`#compdef {{.Name}}
+# zsh completion for {{.Name}}
+_{{.Func}}_complete() {
+	local -a completions
+	local line
+	while IFS= read -r line; do
+		completions+=("${${line%%$'\t'*}//:/\\:}:${line#*$'\t'}")
+	done < <({{.Name}} {{.CompleteArg}} "${(@Q)words[2,CURRENT]}" 2>/dev/null)
+	if (( ${#completions} )); then
+		_describe '{{.Name}}' completions
+	else
+		_files
+	fi
+}
+compdef _{{.Func}}_complete {{.Name}}
+`))
+
+var fishCompletion = template.Must(template.New("fish").Parse(`# fish completion for {{.Name}}
+function __{{.Func}}_complete
+	set -l args (commandline -opc)[2..-1] (commandline -ct)
+	{{.Name}} {{.CompleteArg}} $args 2>/dev/null
+end
+complete -c {{.Name}} -f -a '(__{{.Func}}_complete)'
+`))
+
+func writeCompletionScript(w io.Writer, t *template.Template, name string) error {
+	return t.Execute(w, struct {
+		Name        string
+		Func        string
+		CompleteArg string
+	}{
+		Name:        name,
+		Func:        nonIdentifier.ReplaceAllString(name, "_"),
+		CompleteArg: CompleteArg,
+	})
+}
+
+// WriteBashCompletion writes a bash completion script for the program name,
+// which calls `<name> __complete`. See HandleCompleteArg.
+func WriteBashCompletion(w io.Writer, name string) error {
+	return writeCompletionScript(w, bashCompletion, name)
+}
+
+// WriteZshCompletion writes a zsh completion script for the program name,
+// which calls `<name> __complete`. See HandleCompleteArg.
+func WriteZshCompletion(w io.Writer, name string) error {
+	return writeCompletionScript(w, zshCompletion, name)
+}
+
+// WriteFishCompletion writes a fish completion script for the program name,
+// which calls `<name> __complete`. See HandleCompleteArg.
+func WriteFishCompletion(w io.Writer, name string) error {
+	return writeCompletionScript(w, fishCompletion, name)
+}
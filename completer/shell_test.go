@@ -0,0 +1,139 @@
+package completer
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+func TestWriteCompletions(t *testing.T) {
+	c := func(d prompt.Document) []prompt.Suggest {
+		if d.ShellArgIndex() == 1 {
+			return prompt.FilterHasPrefix([]prompt.Suggest{
+				{Text: "start", Description: "Start a service"},
+				{Text: "stop", Description: "Stop a service\nif running"},
+			}, d.GetWordBeforeCursor(), false)
+		}
+		word, _ := d.ShellWordBeforeCursor()
+		return []prompt.Suggest{
+			{Text: `my\ service`, DisplayText: "my service", Range: &prompt.SuggestRange{Start: -len(word) - 1}},
+			{Text: "dir", AppendText: "/"},
+			{Text: "my stuff"},
+		}
+	}
+
+	scenarioTable := []struct {
+		args     []string
+		expected string
+	}{
+		{
+			args:     nil,
+			expected: "start\tStart a service\nstop\tStop a service if running\n",
+		},
+		{
+			args:     []string{"st"},
+			expected: "start\tStart a service\nstop\tStop a service if running\n",
+		},
+		{
+			args:     []string{"start", "my s"},
+			expected: "my service\t\ndir/\t\nmy stuff\t\n",
+		},
+	}
+	for _, s := range scenarioTable {
+		var buf bytes.Buffer
+		if err := WriteCompletions(&buf, "mycli", s.args, c); err != nil {
+			t.Fatal(err)
+		}
+		if actual := buf.String(); actual != s.expected {
+			t.Errorf("%q: Should be %q, but got %q", s.args, s.expected, actual)
+		}
+	}
+}
+
+func TestWriteBashCompletion(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not installed")
+	}
+	dir, err := ioutil.TempDir("", "go-prompt-shell")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// A fake program printing the arguments given to __complete.
+	program := "#!/bin/sh\n[ \"$1\" = __complete ] || exit 1\nshift\nprintf '%s\\tdescription\\n' \"$@\"\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "my-cli"), []byte(program), 0755); err != nil {
+		t.Fatal(err)
+	}
+	// COMP_WORDS has the words as they are typed, so quotes must be removed before __complete.
+	// They are also split at "=" and ":", which are joined by COMP_LINE.
+	scenarioTable := []struct {
+		words    string
+		line     string
+		expected string
+	}{
+		{words: `'my s'`, expected: "start\nmy\\ s\n"},
+		{words: `'"my s'`, expected: "start\nmy\\ s\n"},
+		{words: `'my\ s'`, expected: "start\nmy\\ s\n"},
+		{words: `"'it'\\''s'"`, expected: "start\nit\\'s\n"},
+		{words: `'"a\"b\\c'`, expected: "start\na\\\"b\\\\c\n"},
+		{words: `--output = js`, line: `--output=js`, expected: "start\njs\n"},
+		{words: `--output =`, line: `--output=`, expected: "start\n\n"},
+		{words: `--output = ''`, line: `'--output= '`, expected: "start\n--output=\n''\n"},
+		{words: `host : 80`, line: `host:80`, expected: "start\n80\n"},
+	}
+	for _, s := range scenarioTable {
+		var script bytes.Buffer
+		if err := WriteBashCompletion(&script, "my-cli"); err != nil {
+			t.Fatal(err)
+		}
+		line := `"${COMP_WORDS[*]}"`
+		if s.line != "" {
+			line = `'my-cli start '` + s.line
+		}
+		script.WriteString(`COMP_WORDS=(my-cli start ` + s.words + `); COMP_CWORD=$((${#COMP_WORDS[@]} - 1)); ` +
+			`COMP_LINE=` + line + `; COMP_POINT=${#COMP_LINE}; _my_cli_complete; printf '%s\n' "${COMPREPLY[@]}"` + "\n")
+
+		cmd := exec.Command("bash", "--norc", "--noprofile")
+		cmd.Env = append(os.Environ(), "PATH="+dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+		cmd.Stdin = &script
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("%v: %s", err, out)
+		}
+		if string(out) != s.expected {
+			t.Errorf("%s: Should be %q, but got %q", s.words, s.expected, out)
+		}
+	}
+}
+
+func TestWriteCompletionScripts(t *testing.T) {
+	for shell, write := range map[string]func(*bytes.Buffer, string) error{
+		"bash": func(b *bytes.Buffer, name string) error { return WriteBashCompletion(b, name) },
+		"zsh":  func(b *bytes.Buffer, name string) error { return WriteZshCompletion(b, name) },
+		"fish": func(b *bytes.Buffer, name string) error { return WriteFishCompletion(b, name) },
+	} {
+		var buf bytes.Buffer
+		if err := write(&buf, "my-cli"); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "my-cli __complete") {
+			t.Errorf("[%s] Should call my-cli __complete, but got %s", shell, buf.String())
+		}
+		if _, err := exec.LookPath(shell); err != nil {
+			continue
+		}
+		// Check the syntax of the script.
+		cmd := exec.Command(shell, "-n")
+		cmd.Stdin = &buf
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Errorf("[%s] Should be valid: %v %s", shell, err, out)
+		}
+	}
+}
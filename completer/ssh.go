@@ -0,0 +1,149 @@
+package completer
+
+import (
+	"bufio"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// SSHHostCompleter is a completer for host names found in ~/.ssh/config and ~/.ssh/known_hosts.
+// Host names are cached until the modification time of those files changes.
+// The part after "@" is completed if the word before the cursor is like `user@host`.
+type SSHHostCompleter struct {
+	IgnoreCase bool
+	// ConfigPath is the path of ssh_config. "~/.ssh/config" is used if empty.
+	ConfigPath string
+	// KnownHostsPath is the path of known_hosts. "~/.ssh/known_hosts" is used if empty.
+	KnownHostsPath string
+
+	mu       sync.Mutex
+	modTimes []time.Time
+	cache    []prompt.Suggest
+}
+
+// Complete returns host names starting with the word before the cursor.
+func (c *SSHHostCompleter) Complete(d prompt.Document) []prompt.Suggest {
+	word := d.GetWordBeforeCursor()
+	i := strings.LastIndexByte(word, '@')
+	suggests := copySuggests(prompt.FilterHasPrefix(c.hosts(), word[i+1:], c.IgnoreCase))
+	if i < 0 {
+		return suggests
+	}
+
+	r := &prompt.SuggestRange{Start: -len([]rune(word[i+1:]))}
+	for j := range suggests {
+		suggests[j].Range = r
+	}
+	return suggests
+}
+
+func (c *SSHHostCompleter) hosts() []prompt.Suggest {
+	configPath, knownHostsPath := c.ConfigPath, c.KnownHostsPath
+	if configPath == "" || knownHostsPath == "" {
+		me, err := user.Current()
+		if err != nil {
+			debug.Log("completer: cannot get current user:" + err.Error())
+			return nil
+		}
+		if configPath == "" {
+			configPath = filepath.Join(me.HomeDir, ".ssh", "config")
+		}
+		if knownHostsPath == "" {
+			knownHostsPath = filepath.Join(me.HomeDir, ".ssh", "known_hosts")
+		}
+	}
+	modTimes := make([]time.Time, 2)
+	for i, p := range []string{configPath, knownHostsPath} {
+		if fi, err := os.Stat(p); err == nil {
+			modTimes[i] = fi.ModTime()
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cache != nil && equalTimes(c.modTimes, modTimes) {
+		return c.cache
+	}
+
+	seen := make(map[string]struct{})
+	suggests := make([]prompt.Suggest, 0, 32)
+	add := func(host, description string) {
+		if _, ok := seen[host]; ok {
+			return
+		}
+		seen[host] = struct{}{}
+		suggests = append(suggests, prompt.Suggest{Text: host, Description: description})
+	}
+	readLines(configPath, func(line string) {
+		key, value := splitSSHConfigLine(line)
+		if !strings.EqualFold(key, "Host") {
+			return
+		}
+		for _, h := range strings.Fields(value) {
+			if !strings.ContainsAny(h, "*?!") {
+				add(h, "ssh config")
+			}
+		}
+	})
+	readLines(knownHostsPath, func(line string) {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && strings.HasPrefix(fields[0], "@") {
+			fields = fields[1:] // Skip markers like @cert-authority.
+		}
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "|") {
+			return // Hashed host names cannot be completed.
+		}
+		for _, h := range strings.Split(fields[0], ",") {
+			if strings.HasPrefix(h, "[") {
+				// [host]:port
+				if j := strings.IndexByte(h, ']'); j > 0 {
+					h = h[1:j]
+				}
+			}
+			if h != "" && !strings.ContainsAny(h, "*?!") {
+				add(h, "known host")
+			}
+		}
+	})
+	c.modTimes, c.cache = modTimes, suggests
+	return suggests
+}
+
+// splitSSHConfigLine splits a line of ssh_config like "Host foo bar" or "Host=foo".
+func splitSSHConfigLine(line string) (key, value string) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return "", ""
+	}
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return line, ""
+	}
+	return line[:i], strings.TrimLeft(line[i:], " \t=")
+}
+
+func readLines(path string, f func(line string)) {
+	file, err := os.Open(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			debug.Log("completer: cannot open file:" + err.Error())
+		}
+		return
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		f(scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		debug.Log("completer: cannot read file:" + err.Error())
+	}
+}
@@ -1,7 +1,9 @@
 package prompt
 
 import (
+	"context"
 	"strings"
+	"time"
 
 	"github.com/c-bata/go-prompt/internal/debug"
 	runewidth "github.com/mattn/go-runewidth"
@@ -25,18 +27,91 @@ var (
 type Suggest struct {
 	Text        string
 	Description string
+
+	// DisplayText is printed in drop down suggestions instead of Text if it is not empty.
+	DisplayText string
+	// Range is the range of text replaced by Text.
+	// If nil, the word before the cursor (until the word separator) is replaced.
+	Range *SuggestRange
+	// AppendText is inserted after Text when the suggestion is accepted, e.g. " " or "/".
+	AppendText string
+	// CursorOffset moves the cursor after the suggestion is inserted.
+	// A negative value moves it to the left.
+	CursorOffset int
+	// Style overrides colors of the suggestion in drop down suggestions.
+	Style *SuggestStyle
+	// Meta holds an arbitrary value for callers.
+	Meta interface{}
+	// Group is the name of the section in drop down suggestions, e.g. "Commands" or "Flags".
+	// Suggestions are gathered by group in the order of their first appearance,
+	// and a header is shown above each group in the list layout.
+	Group string
+
+	groupHeader bool // groupHeader is the header row of a group, which cannot be selected.
+}
+
+// SuggestRange is the range of text which is replaced by a suggestion.
+// Start and End are offsets in runes relative to the cursor position,
+// so {Start: -3, End: 1} replaces three characters before the cursor and one after it.
+type SuggestRange struct {
+	Start int
+	End   int
+}
+
+// SuggestStyle represents colors of a suggestion which is not selected in drop down suggestions.
+type SuggestStyle struct {
+	TextColor            Color
+	BGColor              Color
+	DescriptionTextColor Color
+	DescriptionBGColor   Color
+}
+
+func (s *Suggest) displayText() string {
+	if s.DisplayText != "" {
+		return s.DisplayText
+	}
+	return s.Text
 }
 
+// CompletionStyle to switch the behavior of Tab key.
+type CompletionStyle string
+
+const (
+	// MenuCompletion selects the next suggestion in drop down suggestions on every Tab.
+	MenuCompletion CompletionStyle = "menu"
+	// CommonPrefixCompletion inserts the longest common prefix of suggestions on Tab like bash,
+	// and accepts the suggestion if it is the only one. The second Tab in a row
+	// selects the next suggestion like MenuCompletion.
+	CommonPrefixCompletion CompletionStyle = "common-prefix"
+)
+
+// AcceptHook is called when the user accepts a suggestion, with the document before it is inserted.
+type AcceptHook func(d Document, s Suggest)
+
 // CompletionManager manages which suggestion is now selected.
 type CompletionManager struct {
 	selected  int // -1 means nothing one is selected.
 	tmp       []Suggest
+	grouped   bool // tmp contains the headers of groups.
 	max       uint16
 	completer Completer
 
 	verticalScroll int
 	wordSeparator  string
 	showAtStart    bool
+	layout         CompletionLayout
+	shellQuoting   bool
+	gridColumns    int    // The number of columns in the grid layout, which is updated with suggestions.
+	width          uint16 // The width of the terminal.
+
+	// These are used only if the asynchronous or streaming completer is set.
+	asyncCompleter StreamingCompleter
+	debounce       time.Duration
+	asyncCh        chan asyncSuggestions
+	requested      *Document
+	cancel         context.CancelFunc
+	seq            int
+	loading        bool
 }
 
 // GetSelectedSuggestion returns the selected item.
@@ -53,39 +128,184 @@ func (c *CompletionManager) GetSelectedSuggestion() (s Suggest, ok bool) {
 
 // GetSuggestions returns the list of suggestion.
 func (c *CompletionManager) GetSuggestions() []Suggest {
-	return c.tmp
+	if !c.grouped {
+		return c.tmp
+	}
+	suggests := make([]Suggest, 0, len(c.tmp))
+	for _, s := range c.tmp {
+		if !s.groupHeader {
+			suggests = append(suggests, s)
+		}
+	}
+	return suggests
+}
+
+// setSuggestions sets the suggestions, which are grouped with headers in the list layout.
+// It returns the row of each suggestion.
+func (c *CompletionManager) setSuggestions(suggests []Suggest) (rows []int) {
+	defer c.updateGridColumns()
+	c.grouped = false
+	if c.layout != GridLayout {
+		if grouped, rows := groupSuggestions(suggests); rows != nil {
+			c.tmp, c.grouped = grouped, true
+			return rows
+		}
+	}
+	c.tmp = suggests
+	rows = make([]int, len(suggests))
+	for i := range rows {
+		rows[i] = i
+	}
+	return rows
+}
+
+// groupSuggestions gathers suggestions by group in the order of their first appearance,
+// and inserts a header above each named group. It returns nil if no suggestion has a group.
+func groupSuggestions(suggests []Suggest) (grouped []Suggest, rows []int) {
+	var names []string
+	members := make(map[string][]int)
+	for i := range suggests {
+		name := suggests[i].Group
+		if _, ok := members[name]; !ok {
+			names = append(names, name)
+		}
+		members[name] = append(members[name], i)
+	}
+	if len(names) == 1 && names[0] == "" {
+		return nil, nil
+	}
+
+	grouped = make([]Suggest, 0, len(suggests)+len(names))
+	rows = make([]int, len(suggests))
+	for _, name := range names {
+		if name != "" {
+			grouped = append(grouped, Suggest{Text: name, groupHeader: true})
+		}
+		for _, i := range members[name] {
+			rows[i] = len(grouped)
+			grouped = append(grouped, suggests[i])
+		}
+	}
+	return grouped, rows
 }
 
 // Reset to select nothing.
+// If the asynchronous completer is set, it clears the suggestions instead of requesting them
+// for the empty document, which is usually the most expensive one.
 func (c *CompletionManager) Reset() {
 	c.selected = -1
 	c.verticalScroll = 0
+	if c.asyncCompleter != nil {
+		c.resetAsync()
+		return
+	}
 	c.Update(*NewDocument())
 }
 
 // Update to update the suggestions.
+// If the asynchronous completer is set, it starts to update them in background.
 func (c *CompletionManager) Update(in Document) {
-	c.tmp = c.completer(in)
+	if c.asyncCompleter != nil {
+		c.updateAsync(in)
+		return
+	}
+	c.setSuggestions(c.completer(in))
 }
 
-// Previous to select the previous suggestion item.
+// Previous to select the previous suggestion item. Group headers are skipped.
 func (c *CompletionManager) Previous() {
-	if c.verticalScroll == c.selected && c.selected > 0 {
-		c.verticalScroll--
-	}
 	c.selected--
+	for c.selected >= 0 && c.tmp[c.selected].groupHeader {
+		c.selected--
+	}
 	c.update()
 }
 
-// Next to select the next suggestion item.
+// Next to select the next suggestion item. Group headers are skipped.
 func (c *CompletionManager) Next() {
-	if c.verticalScroll+int(c.max)-1 == c.selected {
-		c.verticalScroll++
-	}
 	c.selected++
+	for c.selected < len(c.tmp) && c.tmp[c.selected].groupHeader {
+		c.selected++
+	}
 	c.update()
 }
 
+// scrollToSelected scrolls drop down suggestions to show the selected item,
+// and the header of its group if it is just above.
+func (c *CompletionManager) scrollToSelected() {
+	if c.selected < 0 {
+		return
+	}
+	top := c.selected
+	if top > 0 && c.tmp[top-1].groupHeader {
+		top--
+	}
+	if top < c.verticalScroll {
+		c.verticalScroll = top
+	}
+	if max := int(c.max); c.selected >= c.verticalScroll+max {
+		c.verticalScroll = c.selected - max + 1
+	}
+}
+
+// replaceRange returns the number of runes before and after the cursor replaced by the suggestion.
+func (c *CompletionManager) replaceRange(d *Document, s Suggest) (before, after int) {
+	if s.Range == nil && c.shellQuoting {
+		w, _ := d.shellWordBeforeCursor()
+		return d.cursorPosition - w.start, 0
+	}
+	if s.Range == nil {
+		return len([]rune(d.GetWordBeforeCursorUntilSeparator(c.wordSeparator))), 0
+	}
+	before, after = -s.Range.Start, s.Range.End
+	if n := len([]rune(d.TextBeforeCursor())); before > n {
+		before = n
+	}
+	if n := len([]rune(d.TextAfterCursor())); after > n {
+		after = n
+	}
+	if before < 0 {
+		before = 0
+	}
+	if after < 0 {
+		after = 0
+	}
+	return before, after
+}
+
+// replacedText returns the text before the cursor which is replaced by the suggestion.
+func (c *CompletionManager) replacedText(d *Document, s Suggest) string {
+	before, _ := c.replaceRange(d, s)
+	text := []rune(d.TextBeforeCursor())
+	return string(text[len(text)-before:])
+}
+
+// insertedText returns Suggest.Text quoted like the word before the cursor if shell quoting is enabled.
+func (c *CompletionManager) insertedText(d *Document, s Suggest) string {
+	if s.Range != nil || !c.shellQuoting {
+		return s.Text
+	}
+	_, quote := d.ShellWordBeforeCursor()
+	return QuoteShellWord(s.Text, quote)
+}
+
+// commonPrefix returns the longest common prefix of the suggestions.
+func commonPrefix(suggests []Suggest) string {
+	if len(suggests) == 0 {
+		return ""
+	}
+	prefix := []rune(suggests[0].Text)
+	for i := 1; i < len(suggests); i++ {
+		text := []rune(suggests[i].Text)
+		n := 0
+		for n < len(prefix) && n < len(text) && prefix[n] == text[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return string(prefix)
+}
+
 // Completing returns whether the CompletionManager selects something one.
 func (c *CompletionManager) Completing() bool {
 	return c.selected != -1
@@ -98,11 +318,13 @@ func (c *CompletionManager) update() {
 	}
 
 	if c.selected >= len(c.tmp) {
-		c.Reset()
+		c.selected = -1
+		c.verticalScroll = 0
 	} else if c.selected < -1 {
 		c.selected = len(c.tmp) - 1
 		c.verticalScroll = len(c.tmp) - max
 	}
+	c.scrollToSelected()
 }
 
 func deleteBreakLineCharacters(s string) string {
@@ -159,7 +381,7 @@ func formatSuggestions(suggests []Suggest, max int) (new []Suggest, width int) {
 
 	left := make([]string, num)
 	for i := 0; i < num; i++ {
-		left[i] = suggests[i].Text
+		left[i] = suggests[i].displayText()
 	}
 	right := make([]string, num)
 	for i := 0; i < num; i++ {
@@ -173,7 +395,7 @@ func formatSuggestions(suggests []Suggest, max int) (new []Suggest, width int) {
 	right, rightWidth := formatTexts(right, max-leftWidth, rightPrefix, rightSuffix)
 
 	for i := 0; i < num; i++ {
-		new[i] = Suggest{Text: left[i], Description: right[i]}
+		new[i] = Suggest{Text: left[i], Description: right[i], Style: suggests[i].Style}
 	}
 	return new, leftWidth + rightWidth
 }
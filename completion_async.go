@@ -0,0 +1,147 @@
+package prompt
+
+import (
+	"context"
+	"time"
+)
+
+// CompleterContext should return the suggest item from Document like Completer.
+// The context is canceled when the document is changed before it returns.
+type CompleterContext func(ctx context.Context, d Document) []Suggest
+
+// StreamingCompleter should return a channel which receives batches of the suggest item
+// from Document, and close it when all of them are sent. The context is canceled when
+// the document is changed, so the completer should stop sending then.
+type StreamingCompleter func(ctx context.Context, d Document) <-chan []Suggest
+
+const loadingIndicator = "Loading..."
+
+type asyncSuggestions struct {
+	seq         int
+	suggestions []Suggest
+	append      bool
+}
+
+func streamOnce(fn CompleterContext) StreamingCompleter {
+	return func(ctx context.Context, d Document) <-chan []Suggest {
+		ch := make(chan []Suggest, 1)
+		if ctx.Err() == nil {
+			ch <- fn(ctx, d)
+		}
+		close(ch)
+		return ch
+	}
+}
+
+// Loading returns whether the asynchronous completer is running for the latest document
+// and no suggestions for it have arrived yet.
+func (c *CompletionManager) Loading() bool {
+	return c.loading
+}
+
+func (c *CompletionManager) updateAsync(in Document) {
+	if c.requested != nil && c.requested.Text == in.Text && c.requested.cursorPosition == in.cursorPosition {
+		return
+	}
+	if c.cancel != nil {
+		c.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.requested = &in
+	c.seq++
+	c.loading = true
+	// The selected index points to the previous suggestions.
+	c.selected = -1
+	c.verticalScroll = 0
+
+	go func(seq int, fn StreamingCompleter, debounce time.Duration, asyncCh chan asyncSuggestions) {
+		if debounce > 0 {
+			t := time.NewTimer(debounce)
+			defer t.Stop()
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+		}
+
+		first := true
+		send := func(suggestions []Suggest) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case asyncCh <- asyncSuggestions{seq: seq, suggestions: suggestions, append: !first}:
+				first = false
+				return true
+			}
+		}
+
+		ch := fn(ctx, in)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case batch, ok := <-ch:
+				if !ok {
+					if first {
+						send(nil)
+					}
+					return
+				}
+				if !send(batch) {
+					return
+				}
+			}
+		}
+	}(c.seq, c.asyncCompleter, c.debounce, c.asyncCh)
+}
+
+// resetAsync cancels the running completer and clears the suggestions.
+func (c *CompletionManager) resetAsync() {
+	c.cancelAsync()
+	c.setSuggestions(nil)
+}
+
+// cancelAsync cancels the running completer, so that it does not leak after the loop exits.
+func (c *CompletionManager) cancelAsync() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	c.requested = nil
+	c.seq++
+	c.loading = false
+}
+
+// applyAsync applies the result of the asynchronous completer if it is still current.
+func (c *CompletionManager) applyAsync(s asyncSuggestions) bool {
+	if s.seq != c.seq {
+		return false
+	}
+	c.loading = false
+	if s.append {
+		// Keep the selected item even if appended ones are grouped before it.
+		selected := -1
+		if c.selected >= 0 {
+			selected = 0
+			for i := 0; i < c.selected; i++ {
+				if !c.tmp[i].groupHeader {
+					selected++
+				}
+			}
+		}
+		rows := c.setSuggestions(append(c.GetSuggestions(), s.suggestions...))
+		if selected >= 0 {
+			c.selected = rows[selected]
+			c.scrollToSelected()
+		}
+		return true
+	}
+	c.setSuggestions(s.suggestions)
+	if c.selected >= len(c.tmp) || c.selected >= 0 && c.tmp[c.selected].groupHeader {
+		c.selected = -1
+		c.verticalScroll = 0
+	}
+	return true
+}
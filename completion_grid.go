@@ -0,0 +1,149 @@
+package prompt
+
+// CompletionLayout to switch the layout of drop down suggestions.
+type CompletionLayout string
+
+const (
+	// ListLayout shows a suggestion and its description per row.
+	ListLayout CompletionLayout = "list"
+	// GridLayout packs suggestions into as many columns as the terminal width allows,
+	// and shows the description of the selected suggestion in a footer.
+	GridLayout CompletionLayout = "grid"
+)
+
+// gridSize returns the number of columns which is calculated at rendering, and the maximum number of rows.
+func (c *CompletionManager) gridSize() (columns, maxRows int) {
+	columns, maxRows = c.gridColumns, int(c.max)
+	if columns < 1 {
+		columns = 1
+	}
+	if maxRows < 1 {
+		maxRows = 1
+	}
+	return columns, maxRows
+}
+
+func (c *CompletionManager) gridPerPage() int {
+	columns, maxRows := c.gridSize()
+	return columns * maxRows
+}
+
+// gridPage returns the range of suggestions on the page which includes the selected one,
+// and the number of rows on it. Suggestions are arranged in column-major order.
+func (c *CompletionManager) gridPage() (start, end, rows int) {
+	columns, _ := c.gridSize()
+	perPage := c.gridPerPage()
+	if c.selected > 0 {
+		start = c.selected / perPage * perPage
+	}
+	end = start + perPage
+	if end > len(c.tmp) {
+		end = len(c.tmp)
+	}
+	rows = (end - start + columns - 1) / columns
+	return start, end, rows
+}
+
+// gridPageRows returns the number of rows on the page.
+func (c *CompletionManager) gridPageRows(page int) int {
+	columns, _ := c.gridSize()
+	perPage := c.gridPerPage()
+	n := len(c.tmp) - page*perPage
+	if n > perPage {
+		n = perPage
+	}
+	return (n + columns - 1) / columns
+}
+
+// gridPosition returns the page, column and row of the suggestion at i.
+func (c *CompletionManager) gridPosition(i int) (page, column, row int) {
+	perPage := c.gridPerPage()
+	page = i / perPage
+	rows := c.gridPageRows(page)
+	return page, i % perPage / rows, i % perPage % rows
+}
+
+// moveColumn selects the suggestion in the same row of the column at the offset,
+// which may be on the next or previous page. The row is clamped on a shorter page.
+func (c *CompletionManager) moveColumn(offset int) {
+	columns, _ := c.gridSize()
+	page, column, row := c.gridPosition(c.selected)
+	column += offset
+	if column >= columns {
+		page, column = page+1, 0
+	} else if column < 0 {
+		page, column = page-1, columns-1
+	}
+	if page < 0 {
+		return
+	}
+	rows := c.gridPageRows(page)
+	if rows <= 0 {
+		return
+	}
+	if row >= rows {
+		row = rows - 1
+	}
+	if i := page*c.gridPerPage() + column*rows + row; i < len(c.tmp) {
+		c.selected = i
+	}
+}
+
+// NextColumn to select the suggestion in the next column of the grid layout.
+func (c *CompletionManager) NextColumn() {
+	if c.selected == -1 {
+		c.Next()
+		return
+	}
+	c.moveColumn(1)
+}
+
+// PreviousColumn to select the suggestion in the previous column of the grid layout.
+func (c *CompletionManager) PreviousColumn() {
+	if c.selected < 0 {
+		return
+	}
+	c.moveColumn(-1)
+}
+
+// setWidth updates the grid layout for the width of the terminal.
+func (c *CompletionManager) setWidth(width uint16) {
+	c.width = width
+	c.updateGridColumns()
+}
+
+// updateGridColumns calculates the number of columns in the grid layout,
+// which fit the widest suggestion in the terminal width.
+func (c *CompletionManager) updateGridColumns() {
+	c.gridColumns = 0
+	if c.layout != GridLayout || len(c.tmp) == 0 || c.width == 0 {
+		return
+	}
+	texts := make([]string, len(c.tmp))
+	for i := range c.tmp {
+		texts[i] = c.tmp[i].displayText()
+	}
+	// -1 to avoid the line wrap at the last column.
+	maxWidth := int(c.width) - 1
+	if _, width := formatTexts(texts, maxWidth, leftPrefix, leftSuffix); width > 0 {
+		c.gridColumns = maxWidth / width
+	}
+}
+
+// NextPage to select the first suggestion on the next page of the grid layout.
+func (c *CompletionManager) NextPage() {
+	if c.selected == -1 {
+		c.Next()
+		return
+	}
+	if _, end, _ := c.gridPage(); end < len(c.tmp) {
+		c.selected = end
+	}
+}
+
+// PreviousPage to select the first suggestion on the previous page of the grid layout.
+func (c *CompletionManager) PreviousPage() {
+	if start, _, _ := c.gridPage(); start > 0 {
+		c.selected = start - c.gridPerPage()
+	}
+}
@@ -1,8 +1,10 @@
 package prompt
 
 import (
+	"context"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestFormatShortSuggestion(t *testing.T) {
@@ -26,6 +28,18 @@ func TestFormatShortSuggestion(t *testing.T) {
 			max:     100,
 			exWidth: 6,
 		},
+		{
+			in: []Suggest{
+				{Text: "foo", DisplayText: "foo/"},
+				{Text: "bar", DisplayText: "bar/", Meta: 1},
+			},
+			expected: []Suggest{
+				{Text: " foo/ "},
+				{Text: " bar/ "},
+			},
+			max:     100,
+			exWidth: 6,
+		},
 		{
 			in: []Suggest{
 				{Text: "apple", Description: "This is apple."},
@@ -204,3 +218,308 @@ func TestFormatText(t *testing.T) {
 		}
 	}
 }
+
+func TestAsyncCompletion(t *testing.T) {
+	started := make(chan string, 2)
+	c := NewCompletionManager(nil, 6)
+	c.asyncCompleter = streamOnce(func(ctx context.Context, d Document) []Suggest {
+		started <- d.Text
+		if d.Text == "a" {
+			<-ctx.Done()
+			return []Suggest{{Text: "stale"}}
+		}
+		return []Suggest{{Text: d.Text + "ple"}}
+	})
+	c.asyncCh = make(chan asyncSuggestions)
+
+	c.Update(Document{Text: "a", cursorPosition: 1})
+	<-started
+	if !c.Loading() {
+		t.Errorf("Should be loading")
+	}
+	c.Update(Document{Text: "ap", cursorPosition: 2})
+	<-started
+
+	select {
+	case r := <-c.asyncCh:
+		if !c.applyAsync(r) {
+			t.Errorf("The result for the latest document should be applied")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timeout")
+	}
+	if c.Loading() {
+		t.Errorf("Should not be loading")
+	}
+	if expected := []Suggest{{Text: "apple"}}; !reflect.DeepEqual(c.GetSuggestions(), expected) {
+		t.Errorf("Want %#v, but got %#v", expected, c.GetSuggestions())
+	}
+	if c.applyAsync(asyncSuggestions{seq: c.seq - 1}) {
+		t.Errorf("The result for an old document should not be applied")
+	}
+}
+
+func TestAsyncCompletionReset(t *testing.T) {
+	started := make(chan string, 2)
+	c := NewCompletionManager(nil, 6)
+	c.asyncCompleter = streamOnce(func(ctx context.Context, d Document) []Suggest {
+		started <- d.Text
+		return []Suggest{{Text: d.Text + "ple"}}
+	})
+	c.asyncCh = make(chan asyncSuggestions, 1)
+
+	c.Update(Document{Text: "a", cursorPosition: 1})
+	<-started
+	r := <-c.asyncCh
+	c.Reset()
+	if c.applyAsync(r) {
+		t.Errorf("The result before Reset should not be applied")
+	}
+	if c.Loading() || len(c.GetSuggestions()) != 0 {
+		t.Errorf("Should be cleared, but got %#v", c.GetSuggestions())
+	}
+	select {
+	case text := <-started:
+		t.Errorf("Reset should not start the completer, but got %q", text)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, ok := <-c.asyncCompleter(ctx, Document{}); ok {
+		t.Errorf("The completer should not run with the canceled context")
+	}
+	if len(started) != 0 {
+		t.Errorf("Should not be started, but got %q", <-started)
+	}
+}
+
+func TestStreamingCompletion(t *testing.T) {
+	c := NewCompletionManager(nil, 6)
+	c.asyncCompleter = func(ctx context.Context, d Document) <-chan []Suggest {
+		ch := make(chan []Suggest)
+		go func() {
+			defer close(ch)
+			ch <- []Suggest{{Text: "apple"}, {Text: "apricot"}}
+			ch <- []Suggest{{Text: "avocado"}}
+		}()
+		return ch
+	}
+	c.asyncCh = make(chan asyncSuggestions)
+
+	c.Update(Document{Text: "a", cursorPosition: 1})
+	c.applyAsync(<-c.asyncCh)
+	c.Next()
+	c.Next()
+	c.applyAsync(<-c.asyncCh)
+
+	expected := []Suggest{{Text: "apple"}, {Text: "apricot"}, {Text: "avocado"}}
+	if !reflect.DeepEqual(c.GetSuggestions(), expected) {
+		t.Errorf("Want %#v, but got %#v", expected, c.GetSuggestions())
+	}
+	if s, ok := c.GetSelectedSuggestion(); !ok || s.Text != "apricot" {
+		t.Errorf("Selected suggestion should be kept, but got %#v", s)
+	}
+}
+
+func TestGridNavigation(t *testing.T) {
+	c := NewCompletionManager(nil, 3)
+	c.layout = GridLayout
+	c.gridColumns = 2
+	for i := 0; i < 10; i++ {
+		c.tmp = append(c.tmp, Suggest{Text: string(rune('a' + i))})
+	}
+
+	c.Next()
+	if start, end, rows := c.gridPage(); start != 0 || end != 6 || rows != 3 {
+		t.Errorf("Unexpected page %d, %d, %d", start, end, rows)
+	}
+	c.NextColumn()
+	if c.selected != 3 {
+		t.Errorf("Should be 3, but got %d", c.selected)
+	}
+	c.PreviousColumn()
+	if c.selected != 0 {
+		t.Errorf("Should be 0, but got %d", c.selected)
+	}
+
+	c.NextPage()
+	if start, end, rows := c.gridPage(); c.selected != 6 || start != 6 || end != 10 || rows != 2 {
+		t.Errorf("Unexpected page %d, %d, %d selected %d", start, end, rows, c.selected)
+	}
+	c.NextColumn()
+	if c.selected != 8 {
+		t.Errorf("Should be 8, but got %d", c.selected)
+	}
+	c.NextPage()
+	if c.selected != 8 {
+		t.Errorf("Should stay on the last page, but got %d", c.selected)
+	}
+	c.PreviousPage()
+	if c.selected != 0 {
+		t.Errorf("Should be 0, but got %d", c.selected)
+	}
+}
+
+func TestGroupedNavigation(t *testing.T) {
+	suggests := []Suggest{
+		{Text: "add", Group: "Commands"},
+		{Text: "--all", Group: "Flags"},
+		{Text: "commit", Group: "Commands"},
+		{Text: "--force", Group: "Flags"},
+	}
+	c := NewCompletionManager(func(Document) []Suggest { return suggests }, 3)
+	c.Update(Document{})
+
+	expected := []Suggest{suggests[0], suggests[2], suggests[1], suggests[3]}
+	if actual := c.GetSuggestions(); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+
+	scenarioTable := []struct {
+		next           bool
+		expected       string
+		verticalScroll int
+	}{
+		{next: true, expected: "add", verticalScroll: 0},
+		{next: true, expected: "commit", verticalScroll: 0},
+		{next: true, expected: "--all", verticalScroll: 2},
+		{next: true, expected: "--force", verticalScroll: 3},
+		{next: true, expected: "", verticalScroll: 0},
+		{next: false, expected: "--force", verticalScroll: 3},
+		{next: false, expected: "--all", verticalScroll: 3},
+		{next: false, expected: "commit", verticalScroll: 2},
+		{next: false, expected: "add", verticalScroll: 0},
+		{next: false, expected: "", verticalScroll: 0},
+	}
+	for i, s := range scenarioTable {
+		if s.next {
+			c.Next()
+		} else {
+			c.Previous()
+		}
+		actual, _ := c.GetSelectedSuggestion()
+		if actual.Text != s.expected || c.verticalScroll != s.verticalScroll {
+			t.Errorf("%d: Should be %#v at %d, but got %#v at %d", i, s.expected, s.verticalScroll, actual.Text, c.verticalScroll)
+		}
+	}
+}
+
+func TestGroupSuggestions(t *testing.T) {
+	suggests := []Suggest{
+		{Text: "a"},
+		{Text: "b", Group: "B"},
+		{Text: "c"},
+	}
+	expected := []Suggest{
+		{Text: "a"},
+		{Text: "c"},
+		{Text: "B", groupHeader: true},
+		{Text: "b", Group: "B"},
+	}
+	actual, rows := groupSuggestions(suggests)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, actual)
+	}
+	if !reflect.DeepEqual(rows, []int{0, 3, 1}) {
+		t.Errorf("Should be %#v, but got %#v", []int{0, 3, 1}, rows)
+	}
+	if actual, rows := groupSuggestions([]Suggest{{Text: "a"}}); actual != nil || rows != nil {
+		t.Errorf("Should be nil without groups, but got %#v", actual)
+	}
+}
+
+func TestStreamingGroupedCompletion(t *testing.T) {
+	c := NewCompletionManager(nil, 6)
+	c.setSuggestions([]Suggest{{Text: "add", Group: "Commands"}, {Text: "--all", Group: "Flags"}})
+	c.Next()
+	c.Next()
+	c.applyAsync(asyncSuggestions{append: true, suggestions: []Suggest{{Text: "commit", Group: "Commands"}}})
+
+	if s, ok := c.GetSelectedSuggestion(); !ok || s.Text != "--all" {
+		t.Errorf("Selected suggestion should be kept, but got %#v", s)
+	}
+	if c.selected != 4 {
+		t.Errorf("Should be 4, but got %d", c.selected)
+	}
+}
+
+func TestSuggestIsComparable(t *testing.T) {
+	// Suggest is used as a map key by callers.
+	seen := map[Suggest]bool{{Text: "a"}: true}
+	if !seen[Suggest{Text: "a"}] || (Suggest{Text: "a"}) == (Suggest{Text: "b"}) {
+		t.Errorf("Suggest should be comparable")
+	}
+}
+
+func TestAsyncCompletionResetsSelection(t *testing.T) {
+	c := NewCompletionManager(nil, 6)
+	c.asyncCompleter = streamOnce(func(ctx context.Context, d Document) []Suggest {
+		return []Suggest{{Text: d.Text + "ple"}}
+	})
+	c.asyncCh = make(chan asyncSuggestions)
+
+	c.Update(Document{Text: "a", cursorPosition: 1})
+	c.applyAsync(<-c.asyncCh)
+	c.Next()
+	if _, ok := c.GetSelectedSuggestion(); !ok {
+		t.Fatal("Should be selected")
+	}
+	c.Update(Document{Text: "ap", cursorPosition: 2})
+	if s, ok := c.GetSelectedSuggestion(); ok {
+		t.Errorf("The selection of the previous suggestions should be reset, but got %#v", s)
+	}
+	c.applyAsync(<-c.asyncCh)
+}
+
+func TestGridNavigationAcrossPages(t *testing.T) {
+	c := NewCompletionManager(nil, 3)
+	c.layout = GridLayout
+	c.gridColumns = 2
+	for i := 0; i < 8; i++ {
+		c.tmp = append(c.tmp, Suggest{Text: string(rune('a' + i))})
+	}
+
+	// The first page has 3 rows and the last page has only one.
+	scenarioTable := []struct {
+		selected int
+		next     bool
+		expected int
+	}{
+		{selected: 6, next: false, expected: 3},
+		{selected: 7, next: false, expected: 6},
+		{selected: 3, next: true, expected: 6},
+		{selected: 5, next: true, expected: 6},
+		{selected: 6, next: true, expected: 7},
+		{selected: 7, next: true, expected: 7},
+		{selected: 1, next: false, expected: 1},
+	}
+	for _, s := range scenarioTable {
+		c.selected = s.selected
+		if s.next {
+			c.NextColumn()
+		} else {
+			c.PreviousColumn()
+		}
+		if c.selected != s.expected {
+			t.Errorf("%d (next: %v): Should be %d, but got %d", s.selected, s.next, s.expected, c.selected)
+		}
+	}
+}
+
+func TestGridColumns(t *testing.T) {
+	c := NewCompletionManager(func(Document) []Suggest {
+		return []Suggest{{Text: "abc"}, {Text: "defgh"}, {Text: "i"}}
+	}, 3)
+	c.layout = GridLayout
+	c.setWidth(30)
+	c.Update(Document{})
+	// Each column is 7 wide with the prefix and suffix, and the last column is not used.
+	if c.gridColumns != 4 {
+		t.Errorf("Should be %d, but got %d", 4, c.gridColumns)
+	}
+	c.setWidth(15)
+	if c.gridColumns != 2 {
+		t.Errorf("Should be %d, but got %d", 2, c.gridColumns)
+	}
+}
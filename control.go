@@ -0,0 +1,81 @@
+package prompt
+
+// action is posted from other goroutines and applied inside the Run/Input loop.
+type action func() (shouldExit bool, exec *Exec)
+
+func (p *Prompt) post(a action) {
+	p.actionCh <- a
+}
+
+func (p *Prompt) apply(a action) (shouldExit bool, exec *Exec) {
+	p.prevText = p.buf.Text()
+	return a()
+}
+
+// The following methods control a running prompt. They are safe to call from any goroutine
+// because they are applied inside the Run (or Input) loop instead of mutating the buffer directly.
+// Please caution that they block while the loop isn't reading input (e.g. while the executor
+// is running) once too many actions are queued.
+
+// SetText replaces the text in the buffer and moves the cursor to the end of it.
+func (p *Prompt) SetText(text string) {
+	p.post(func() (bool, *Exec) {
+		p.buf = NewBuffer()
+		p.buf.InsertText(text, false, true)
+		p.completion.Reset()
+		return false, nil
+	})
+}
+
+// InsertText inserts the text at the cursor position.
+func (p *Prompt) InsertText(text string) {
+	p.post(func() (bool, *Exec) {
+		p.buf.InsertText(text, false, true)
+		return false, nil
+	})
+}
+
+// MoveCursor moves the cursor by the given number of characters.
+// A negative count moves it to the left.
+func (p *Prompt) MoveCursor(count int) {
+	p.post(func() (bool, *Exec) {
+		if count < 0 {
+			p.buf.CursorLeft(-count)
+		} else {
+			p.buf.CursorRight(count)
+		}
+		return false, nil
+	})
+}
+
+// OpenCompletion updates suggestions for the current text and selects the first one like Tab.
+func (p *Prompt) OpenCompletion() {
+	p.post(func() (bool, *Exec) {
+		p.completion.Update(*p.buf.Document())
+		p.completion.Next()
+		return false, nil
+	})
+}
+
+// Submit submits the current text as if the user pressed Enter,
+// regardless of the statement terminator.
+func (p *Prompt) Submit() {
+	p.post(func() (bool, *Exec) {
+		return false, p.submit()
+	})
+}
+
+// SetPrefix changes the prefix string.
+func (p *Prompt) SetPrefix(prefix string) {
+	p.post(func() (bool, *Exec) {
+		p.renderer.prefix = prefix
+		return false, nil
+	})
+}
+
+// Stop stops the Run (or Input) loop.
+func (p *Prompt) Stop() {
+	p.post(func() (bool, *Exec) {
+		return true, nil
+	})
+}
@@ -0,0 +1,25 @@
+package prompt
+
+import "testing"
+
+func TestPromptControl(t *testing.T) {
+	p := newTestPrompt(&testWriter{})
+	p.SetText("world")
+	p.MoveCursor(-5)
+	p.InsertText("hello ")
+	p.SetPrefix(">>> ")
+	p.Submit()
+
+	if got := p.Input(); got != "hello world" {
+		t.Errorf("Should be %q, but got %q", "hello world", got)
+	}
+	if p.renderer.prefix != ">>> " {
+		t.Errorf("Should be %q, but got %q", ">>> ", p.renderer.prefix)
+	}
+
+	p.InsertText("foo")
+	p.Stop()
+	if got := p.Input(); got != "" {
+		t.Errorf("Stop should return empty string, but got %q", got)
+	}
+}
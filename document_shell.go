@@ -0,0 +1,169 @@
+package prompt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ShellQuote represents the quoting style of a shell word.
+type ShellQuote int
+
+const (
+	// ShellQuoteNone means the word is not quoted. Special characters are escaped by backslashes.
+	ShellQuoteNone ShellQuote = iota
+	// ShellQuoteSingle means the word is quoted by single quotes.
+	ShellQuoteSingle
+	// ShellQuoteDouble means the word is quoted by double quotes.
+	ShellQuoteDouble
+)
+
+// shellSpecialChars are escaped by backslashes in unquoted words.
+const shellSpecialChars = " \t\n'\"\\$&;|<>()*?[]{}#!`"
+
+type shellWord struct {
+	text  string // unquoted text
+	start int    // index in a rune array of the raw text
+	quote ShellQuote
+}
+
+// splitShellWords splits s into words like a POSIX shell, removing quotes and escapes.
+// inWord reports whether s ends inside the last word, that is, not after a white space.
+func splitShellWords(s string) (words []shellWord, inWord bool) {
+	var (
+		runes   = []rune(s)
+		cur     strings.Builder
+		word    shellWord
+		quote   = ShellQuoteNone
+		escaped bool
+	)
+	begin := func(i int, q ShellQuote) {
+		if !inWord {
+			inWord = true
+			cur.Reset()
+			word = shellWord{start: i, quote: q}
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case escaped:
+			cur.WriteRune(r)
+			escaped = false
+		case quote == ShellQuoteSingle:
+			if r == '\'' {
+				quote = ShellQuoteNone
+			} else {
+				cur.WriteRune(r)
+			}
+		case quote == ShellQuoteDouble:
+			if r == '"' {
+				quote = ShellQuoteNone
+			} else if r == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+				escaped = true
+			} else {
+				cur.WriteRune(r)
+			}
+		case r == '\\':
+			begin(i, ShellQuoteNone)
+			escaped = true
+		case r == '\'':
+			begin(i, ShellQuoteSingle)
+			quote = ShellQuoteSingle
+		case r == '"':
+			begin(i, ShellQuoteDouble)
+			quote = ShellQuoteDouble
+		case unicode.IsSpace(r):
+			if inWord {
+				word.text = cur.String()
+				words = append(words, word)
+				inWord = false
+			}
+		default:
+			begin(i, ShellQuoteNone)
+			cur.WriteRune(r)
+		}
+	}
+	if inWord {
+		word.text = cur.String()
+		if quote != ShellQuoteNone {
+			word.quote = quote // The quote is still open.
+		}
+		words = append(words, word)
+	}
+	return words, inWord
+}
+
+// ShellArgs returns arguments in the text split like a POSIX shell.
+// Quotes and backslash escapes are removed.
+func (d *Document) ShellArgs() []string {
+	words, _ := splitShellWords(d.Text)
+	args := make([]string, len(words))
+	for i := range words {
+		args[i] = words[i].text
+	}
+	return args
+}
+
+// ShellArgIndex returns the index of the argument under the cursor in ShellArgs.
+// If there is a white space before the cursor, this returns the index of a new argument.
+func (d *Document) ShellArgIndex() int {
+	words, inWord := splitShellWords(d.TextBeforeCursor())
+	if inWord {
+		return len(words) - 1
+	}
+	return len(words)
+}
+
+// ShellWordBeforeCursor returns the unquoted part of the argument before the cursor and its quoting style.
+// So if the text before the cursor is `cat "my fi`, this returns "my fi" and ShellQuoteDouble.
+func (d *Document) ShellWordBeforeCursor() (word string, quote ShellQuote) {
+	w, ok := d.shellWordBeforeCursor()
+	if !ok {
+		return "", ShellQuoteNone
+	}
+	return w.text, w.quote
+}
+
+// FindStartOfPreviousShellWord returns an index of the start of the argument before the cursor,
+// including its quotes and backslash escapes, in the text before the cursor.
+// So if the text before the cursor is `cat "my fi`, this returns 4.
+func (d *Document) FindStartOfPreviousShellWord() int {
+	w, _ := d.shellWordBeforeCursor()
+	return len(string([]rune(d.Text)[:w.start]))
+}
+
+func (d *Document) shellWordBeforeCursor() (shellWord, bool) {
+	words, inWord := splitShellWords(d.TextBeforeCursor())
+	if !inWord {
+		return shellWord{start: d.cursorPosition}, false
+	}
+	return words[len(words)-1], true
+}
+
+// QuoteShellWord quotes or escapes s with the quoting style so that a POSIX shell reads it as one word.
+func QuoteShellWord(s string, quote ShellQuote) string {
+	switch quote {
+	case ShellQuoteSingle:
+		return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+	case ShellQuoteDouble:
+		var b strings.Builder
+		b.WriteRune('"')
+		for _, r := range s {
+			if strings.ContainsRune("\"\\$`", r) {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		}
+		b.WriteRune('"')
+		return b.String()
+	default:
+		var b strings.Builder
+		for _, r := range s {
+			if strings.ContainsRune(shellSpecialChars, r) {
+				b.WriteRune('\\')
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+}
@@ -1253,3 +1253,85 @@ func TestDocument_GetEndOfLinePosition(t *testing.T) {
 		t.Errorf("Should be %#v, got %#v", ex, ac)
 	}
 }
+
+func TestDocumentShellWords(t *testing.T) {
+	pattern := []struct {
+		document  *Document
+		args      []string
+		index     int
+		word      string
+		quote     ShellQuote
+		rawLength int
+	}{
+		{
+			document:  &Document{Text: `cat "my fi`, cursorPosition: 10},
+			args:      []string{"cat", "my fi"},
+			index:     1,
+			word:      "my fi",
+			quote:     ShellQuoteDouble,
+			rawLength: 6,
+		},
+		{
+			document:  &Document{Text: `cat my\ fi`, cursorPosition: 10},
+			args:      []string{"cat", "my fi"},
+			index:     1,
+			word:      "my fi",
+			quote:     ShellQuoteNone,
+			rawLength: 6,
+		},
+		{
+			document:  &Document{Text: `echo 'it'\''s' `, cursorPosition: 15},
+			args:      []string{"echo", "it's"},
+			index:     2,
+			word:      "",
+			quote:     ShellQuoteNone,
+			rawLength: 0,
+		},
+		{
+			document:  &Document{Text: `grep "a \"b\"" file`, cursorPosition: 7},
+			args:      []string{"grep", `a "b"`, "file"},
+			index:     1,
+			word:      "a",
+			quote:     ShellQuoteDouble,
+			rawLength: 2,
+		},
+	}
+
+	for i, p := range pattern {
+		if ac := p.document.ShellArgs(); !reflect.DeepEqual(ac, p.args) {
+			t.Errorf("[%d] Should be %#v, got %#v", i, p.args, ac)
+		}
+		if ac := p.document.ShellArgIndex(); ac != p.index {
+			t.Errorf("[%d] Should be %#v, got %#v", i, p.index, ac)
+		}
+		word, quote := p.document.ShellWordBeforeCursor()
+		if word != p.word || quote != p.quote {
+			t.Errorf("[%d] Should be %#v %#v, got %#v %#v", i, p.word, p.quote, word, quote)
+		}
+		if ac := len(p.document.TextBeforeCursor()[p.document.FindStartOfPreviousShellWord():]); ac != p.rawLength {
+			t.Errorf("[%d] Should be %#v, got %#v", i, p.rawLength, ac)
+		}
+		c := &CompletionManager{shellQuoting: true}
+		if before, _ := c.replaceRange(p.document, Suggest{}); before != p.rawLength {
+			t.Errorf("[%d] Should be %#v, got %#v", i, p.rawLength, before)
+		}
+	}
+}
+
+func TestQuoteShellWord(t *testing.T) {
+	pattern := []struct {
+		in       string
+		quote    ShellQuote
+		expected string
+	}{
+		{in: "my file.txt", quote: ShellQuoteNone, expected: `my\ file.txt`},
+		{in: "my file.txt", quote: ShellQuoteDouble, expected: `"my file.txt"`},
+		{in: `say "$hi"`, quote: ShellQuoteDouble, expected: `"say \"\$hi\""`},
+		{in: "it's", quote: ShellQuoteSingle, expected: `'it'\''s'`},
+	}
+	for _, p := range pattern {
+		if ac := QuoteShellWord(p.in, p.quote); ac != p.expected {
+			t.Errorf("Should be %#v, got %#v", p.expected, ac)
+		}
+	}
+}
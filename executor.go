@@ -0,0 +1,91 @@
+package prompt
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/c-bata/go-prompt/internal/debug"
+)
+
+// ExecutorContext is called when user input something text like Executor.
+// The context is canceled when the user presses Ctrl+c (or the process receives SIGINT)
+// while it is running, so a long-running command can be aborted without exiting the prompt.
+type ExecutorContext func(ctx context.Context, in string) error
+
+type writerKey struct{}
+
+// WriterFromContext returns the writer of Prompt.Writer from the context passed to ExecutorContext.
+// Commands running as jobs with OptionAsyncExecutor should print their output through it
+// not to garble the prompt which is rendered at the same time.
+func WriterFromContext(ctx context.Context) io.Writer {
+	if w, ok := ctx.Value(writerKey{}).(io.Writer); ok {
+		return w
+	}
+	return os.Stdout
+}
+
+// ExecutorErrorHandler is called with the input and the error returned from ExecutorContext.
+type ExecutorErrorHandler func(in string, err error)
+
+// PreExecHook is called with the submitted input before the executor runs.
+// It returns the input to execute, which may be rewritten, and false to skip executing it.
+type PreExecHook func(in string) (out string, ok bool)
+
+// PostExecHook is called after the executor returns.
+type PostExecHook func(in string, result ExecResult)
+
+// ExecResult is the result of the executor passed to PostExecHook.
+type ExecResult struct {
+	// Duration is the time taken by the executor.
+	Duration time.Duration
+	// Err is the error returned from ExecutorContext. It is always nil for Executor.
+	Err error
+}
+
+func (p *Prompt) preExec(e *Exec) *Exec {
+	if e == nil || p.preExecHook == nil {
+		return e
+	}
+	in, ok := p.preExecHook(e.input)
+	if !ok {
+		return nil
+	}
+	return &Exec{input: in}
+}
+
+func (p *Prompt) execute(in string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The terminal is not in raw mode while executing, so Ctrl+c is delivered as SIGINT.
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			debug.Log("Catch SIGINT while executing")
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	result := p.runExecutor(ctx, in)
+	if p.postExecHook != nil {
+		p.postExecHook(in, result)
+	}
+}
+
+func (p *Prompt) runExecutor(ctx context.Context, in string) ExecResult {
+	var err error
+	start := time.Now()
+	if p.executorContext == nil {
+		p.executor(in)
+	} else if err = p.executorContext(context.WithValue(ctx, writerKey{}, p.Writer()), in); err != nil && p.executorErrorHandler != nil {
+		p.executorErrorHandler(in, err)
+	}
+	return ExecResult{Duration: time.Since(start), Err: err}
+}
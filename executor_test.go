@@ -0,0 +1,79 @@
+//go:build !windows
+// +build !windows
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExecuteContextCanceledBySIGINT(t *testing.T) {
+	var reported error
+	p := newTestPrompt(&testWriter{})
+	p.executorContext = func(ctx context.Context, in string) error {
+		if err := syscall.Kill(os.Getpid(), syscall.SIGINT); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(3 * time.Second):
+			return errors.New("timeout")
+		}
+	}
+	p.executorErrorHandler = func(in string, err error) {
+		reported = err
+	}
+
+	p.execute("sleep")
+	if !errors.Is(reported, context.Canceled) {
+		t.Errorf("Should be %v, but got %v", context.Canceled, reported)
+	}
+}
+
+func TestExecHooks(t *testing.T) {
+	var (
+		executed []string
+		results  []ExecResult
+	)
+	p := newTestPrompt(&testWriter{})
+	p.executorContext = func(ctx context.Context, in string) error {
+		executed = append(executed, in)
+		if in == "fail" {
+			return errors.New("failed")
+		}
+		return nil
+	}
+	p.preExecHook = func(in string) (string, bool) {
+		if in == "" {
+			return "", false
+		}
+		return strings.TrimSpace(in), true
+	}
+	p.postExecHook = func(in string, result ExecResult) {
+		results = append(results, result)
+	}
+
+	if e := p.preExec(&Exec{input: ""}); e != nil {
+		t.Errorf("Should be vetoed, but got %#v", e)
+	}
+	for _, in := range []string{" ok ", "fail"} {
+		if e := p.preExec(&Exec{input: in}); e != nil {
+			p.execute(e.input)
+		}
+	}
+
+	if !reflect.DeepEqual(executed, []string{"ok", "fail"}) {
+		t.Errorf("Should be %#v, but got %#v", []string{"ok", "fail"}, executed)
+	}
+	if len(results) != 2 || results[0].Err != nil || results[1].Err == nil {
+		t.Errorf("Unexpected results %#v", results)
+	}
+}
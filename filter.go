@@ -1,6 +1,10 @@
 package prompt
 
-import "strings"
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
 
 // Filter is the type to filter the prompt.Suggestion array.
 type Filter func([]Suggest, string, bool) []Suggest
@@ -50,6 +54,120 @@ func fuzzyMatch(s, sub string) bool {
 	return true
 }
 
+// FilterFuzzyRanked checks whether the completion.Text fuzzy matches sub like FilterFuzzy,
+// and sorts the results by the score of FuzzyMatch. The matched characters are highlighted
+// in drop down suggestions.
+func FilterFuzzyRanked(completions []Suggest, sub string, ignoreCase bool) []Suggest {
+	if sub == "" {
+		return completions
+	}
+
+	type ranked struct {
+		suggest Suggest
+		score   int
+	}
+	r := make([]ranked, 0, len(completions))
+	for i := range completions {
+		score, _, ok := FuzzyMatch(completions[i].Text, sub, ignoreCase)
+		if !ok {
+			continue
+		}
+		r = append(r, ranked{suggest: completions[i], score: score})
+	}
+	sort.SliceStable(r, func(i, j int) bool { return r[i].score > r[j].score })
+
+	ret := make([]Suggest, len(r))
+	for i := range r {
+		ret[i] = r[i].suggest
+	}
+	return ret
+}
+
+const (
+	fuzzyScoreMatch       = 16
+	fuzzyBonusPrefix      = 16
+	fuzzyBonusBoundary    = 8
+	fuzzyBonusCamelCase   = 7
+	fuzzyBonusConsecutive = 8
+	fuzzyPenaltyGap       = 1
+	fuzzyPenaltyLeading   = 1
+	fuzzyMaxPenaltyLead   = 3
+)
+
+// FuzzyMatch checks whether s fuzzy matches sub, and returns the score and
+// the positions of the matched runes in s. A higher score means a better match:
+// a match at the beginning, at word boundaries (after a separator or at camelCase humps)
+// and consecutive matches get bonuses, and gaps between matches get penalties.
+func FuzzyMatch(s, sub string, ignoreCase bool) (score int, positions []int, ok bool) {
+	sRunes := []rune(s)
+	subRunes := []rune(sub)
+	if len(subRunes) == 0 {
+		return 0, nil, true
+	}
+
+	equal := func(a, b rune) bool {
+		if ignoreCase {
+			return unicode.ToLower(a) == unicode.ToLower(b)
+		}
+		return a == b
+	}
+
+	// Try all occurrences of the first rune and keep the best one.
+	for start := range sRunes {
+		if !equal(sRunes[start], subRunes[0]) {
+			continue
+		}
+		p := make([]int, 0, len(subRunes))
+		p = append(p, start)
+		for i, j := start+1, 1; i < len(sRunes) && j < len(subRunes); i++ {
+			if equal(sRunes[i], subRunes[j]) {
+				p = append(p, i)
+				j++
+			}
+		}
+		if len(p) != len(subRunes) {
+			break // Later starts cannot match either.
+		}
+		if sc := fuzzyScore(sRunes, p); !ok || sc > score {
+			score, positions, ok = sc, p, true
+		}
+	}
+	return score, positions, ok
+}
+
+func fuzzyScore(s []rune, positions []int) int {
+	score := 0
+	lead := positions[0]
+	if lead > fuzzyMaxPenaltyLead {
+		lead = fuzzyMaxPenaltyLead
+	}
+	score -= lead * fuzzyPenaltyLeading
+
+	for i, pos := range positions {
+		score += fuzzyScoreMatch
+		switch {
+		case pos == 0:
+			score += fuzzyBonusPrefix + fuzzyBonusBoundary
+		case isWordSeparator(s[pos-1]):
+			score += fuzzyBonusBoundary
+		case unicode.IsLower(s[pos-1]) && unicode.IsUpper(s[pos]):
+			score += fuzzyBonusCamelCase
+		}
+		if i > 0 {
+			if gap := pos - positions[i-1] - 1; gap == 0 {
+				score += fuzzyBonusConsecutive
+			} else {
+				score -= gap * fuzzyPenaltyGap
+			}
+		}
+	}
+	return score
+}
+
+func isWordSeparator(r rune) bool {
+	return unicode.IsSpace(r) || strings.ContainsRune("-_./\\:,;", r)
+}
+
 func filterSuggestions(suggestions []Suggest, sub string, ignoreCase bool, function func(string, string) bool) []Suggest {
 	if sub == "" {
 		return suggestions
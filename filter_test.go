@@ -162,3 +162,70 @@ func TestFuzzyMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestFilterFuzzyRanked(t *testing.T) {
+	var scenarioTable = []struct {
+		scenario   string
+		list       []Suggest
+		substr     string
+		ignoreCase bool
+		expected   []Suggest
+	}{
+		{
+			scenario: "prefix match is ranked higher",
+			list: []Suggest{
+				{Text: "idempotent"},
+				{Text: "deploy"},
+				{Text: "list"},
+			},
+			substr: "dep",
+			expected: []Suggest{
+				{Text: "deploy"},
+				{Text: "idempotent"},
+			},
+		},
+		{
+			scenario: "word boundary and camelCase",
+			list: []Suggest{
+				{Text: "abcdef"},
+				{Text: "get-pod-logs"},
+				{Text: "getPodLogs"},
+			},
+			substr:     "gpl",
+			ignoreCase: true,
+			expected: []Suggest{
+				{Text: "get-pod-logs"},
+				{Text: "getPodLogs"},
+			},
+		},
+		{
+			scenario: "empty string",
+			list: []Suggest{
+				{Text: "abc"},
+			},
+			substr: "",
+			expected: []Suggest{
+				{Text: "abc"},
+			},
+		},
+	}
+
+	for _, s := range scenarioTable {
+		if actual := FilterFuzzyRanked(s.list, s.substr, s.ignoreCase); !reflect.DeepEqual(actual, s.expected) {
+			t.Errorf("%s: Should be %#v, but got %#v", s.scenario, s.expected, actual)
+		}
+	}
+}
+
+func TestFuzzyMatchPrefersBetterPositions(t *testing.T) {
+	_, positions, ok := FuzzyMatch("xa_yb_abc", "abc", false)
+	if !ok {
+		t.Fatal("Should match")
+	}
+	if !reflect.DeepEqual(positions, []int{6, 7, 8}) {
+		t.Errorf("Should be %#v, but got %#v", []int{6, 7, 8}, positions)
+	}
+	if _, _, ok := FuzzyMatch("abc", "abd", false); ok {
+		t.Errorf("Should not match")
+	}
+}
@@ -0,0 +1,125 @@
+package prompt
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// Job is a command which is running in background with OptionAsyncExecutor.
+type Job struct {
+	ID        int
+	Input     string
+	StartedAt time.Time
+
+	cancel context.CancelFunc
+}
+
+// Jobs returns the running jobs ordered by ID.
+func (p *Prompt) Jobs() []Job {
+	p.jobsMu.Lock()
+	defer p.jobsMu.Unlock()
+
+	jobs := make([]Job, 0, len(p.jobs))
+	for _, j := range p.jobs {
+		jobs = append(jobs, *j)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].ID < jobs[j].ID })
+	return jobs
+}
+
+// CancelJob cancels the context passed to the ExecutorContext of the job.
+// It returns false if the job is not running.
+func (p *Prompt) CancelJob(id int) bool {
+	p.jobsMu.Lock()
+	defer p.jobsMu.Unlock()
+
+	j, ok := p.jobs[id]
+	if !ok {
+		return false
+	}
+	j.cancel()
+	return true
+}
+
+func (p *Prompt) startJob(in string) *Job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	p.jobsMu.Lock()
+	if p.jobs == nil {
+		p.jobs = make(map[int]*Job, 4)
+	}
+	p.lastJobID++
+	j := &Job{
+		ID:        p.lastJobID,
+		Input:     in,
+		StartedAt: time.Now(),
+		cancel:    cancel,
+	}
+	p.jobs[j.ID] = j
+	p.jobsMu.Unlock()
+
+	go func() {
+		defer cancel()
+		p.finishJob(j.ID, in, p.runExecutor(ctx, in))
+	}()
+	return j
+}
+
+type jobResult struct {
+	input  string
+	result ExecResult
+}
+
+// finishJob removes the job, and calls PostExecHook inside the Run loop because it may update
+// the state of the prompt like the live prefix. If the loop is not running, it is called directly.
+func (p *Prompt) finishJob(id int, in string, result ExecResult) {
+	p.jobsMu.Lock()
+	delete(p.jobs, id)
+	if p.postExecHook == nil {
+		p.jobsMu.Unlock()
+		return
+	}
+	if p.jobsLoop {
+		p.jobResults = append(p.jobResults, jobResult{input: in, result: result})
+		p.jobsMu.Unlock()
+		select {
+		case p.jobDoneCh <- struct{}{}:
+		default:
+		}
+		return
+	}
+	p.jobsMu.Unlock()
+	p.postExecHook(in, result)
+}
+
+// runJobHooks calls PostExecHook for the jobs finished since the last call.
+func (p *Prompt) runJobHooks() {
+	p.jobsMu.Lock()
+	results := p.jobResults
+	p.jobResults = nil
+	p.jobsMu.Unlock()
+
+	for _, r := range results {
+		p.postExecHook(r.input, r.result)
+	}
+}
+
+// enterJobsLoop makes finished jobs queue PostExecHook for the Run loop.
+func (p *Prompt) enterJobsLoop() {
+	p.jobsMu.Lock()
+	defer p.jobsMu.Unlock()
+	p.jobsLoop = true
+}
+
+// exitJobsLoop cancels the running jobs and calls the queued hooks when the Run loop exits.
+// The hooks of the jobs finished after that are called directly.
+func (p *Prompt) exitJobsLoop() {
+	p.jobsMu.Lock()
+	p.jobsLoop = false
+	for _, j := range p.jobs {
+		j.cancel()
+	}
+	p.jobsMu.Unlock()
+	p.runJobHooks()
+}
@@ -0,0 +1,106 @@
+package prompt
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobs(t *testing.T) {
+	p := newTestPrompt(&testWriter{})
+	started := make(chan struct{})
+	done := make(chan error, 1)
+	p.executorContext = func(ctx context.Context, in string) error {
+		started <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	p.executorErrorHandler = func(in string, err error) {
+		done <- err
+	}
+
+	j := p.startJob("sleep 10")
+	<-started
+	if jobs := p.Jobs(); len(jobs) != 1 || jobs[0].ID != j.ID || jobs[0].Input != "sleep 10" {
+		t.Errorf("Should be a running job, but got %#v", jobs)
+	}
+
+	if p.CancelJob(j.ID + 1) {
+		t.Errorf("CancelJob should return false for an unknown job")
+	}
+	if !p.CancelJob(j.ID) {
+		t.Errorf("CancelJob should return true for a running job")
+	}
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Should be %v, but got %v", context.Canceled, err)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("job is not canceled")
+	}
+
+	for i := 0; i < 100 && len(p.Jobs()) != 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if jobs := p.Jobs(); len(jobs) != 0 {
+		t.Errorf("Should be empty, but got %#v", jobs)
+	}
+}
+
+func TestJobOutputAndPostExecHook(t *testing.T) {
+	w := &testWriter{}
+	p := newTestPrompt(w)
+	p.executorContext = func(ctx context.Context, in string) error {
+		fmt.Fprintf(WriterFromContext(ctx), "output of %s\n", in)
+		return nil
+	}
+	var hooked []string
+	p.postExecHook = func(in string, result ExecResult) {
+		hooked = append(hooked, in)
+	}
+	p.setRendering(true)
+	p.enterJobsLoop()
+	p.startJob("echo")
+
+	select {
+	case <-p.jobDoneCh:
+	case <-time.After(3 * time.Second):
+		t.Fatal("The finished job is not notified to the loop")
+	}
+	if len(hooked) != 0 {
+		t.Errorf("PostExecHook should be called inside the loop, but got %#v", hooked)
+	}
+	p.runJobHooks()
+	if !reflect.DeepEqual(hooked, []string{"echo"}) {
+		t.Errorf("Should be %#v, but got %#v", []string{"echo"}, hooked)
+	}
+
+	// Hooks are not queued for the next loop after the loop exits.
+	p.exitJobsLoop()
+	done := make(chan struct{})
+	p.postExecHook = func(in string, result ExecResult) {
+		hooked = append(hooked, in)
+		close(done)
+	}
+	p.startJob("after")
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("PostExecHook should be called directly after the loop exits")
+	}
+	if len(p.actionCh) != 0 || len(p.jobResults) != 0 {
+		t.Errorf("Nothing should be queued, but got %d actions and %#v", len(p.actionCh), p.jobResults)
+	}
+
+	if len(w.flushed) != 0 {
+		t.Errorf("Output should be queued while rendering, but got %q", w.flushed)
+	}
+	p.printOutput()
+	if expected := "output of echo\n"; !strings.Contains(string(w.flushed), expected) {
+		t.Errorf("Should contain %q, but got %q", expected, w.flushed)
+	}
+}
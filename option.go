@@ -1,5 +1,7 @@
 package prompt
 
+import "time"
+
 // Option is the type to replace default parameters.
 // prompt.New accepts any number of options (this is functional option pattern).
 type Option func(prompt *Prompt) error
@@ -53,6 +55,17 @@ func OptionCompletionWordSeparator(x string) Option {
 	}
 }
 
+// OptionShellQuoting enables shell-aware completion. The whole argument before the cursor,
+// including quotes and backslash escapes, is replaced by the accepted suggestion, and Suggest.Text
+// is quoted like the argument, e.g. "my file" for `"my fi` and my\ file for `my\ fi`.
+// Completers should use Document.ShellWordBeforeCursor to get the unquoted argument.
+func OptionShellQuoting() Option {
+	return func(p *Prompt) error {
+		p.completion.shellQuoting = true
+		return nil
+	}
+}
+
 // OptionLivePrefix to change the prefix dynamically by callback function
 func OptionLivePrefix(f func() (prefix string, useLivePrefix bool)) Option {
 	return func(p *Prompt) error {
@@ -189,6 +202,22 @@ func OptionScrollbarBGColor(x Color) Option {
 	}
 }
 
+// OptionGroupHeaderTextColor to change a text color of group headers in drop down suggestions.
+func OptionGroupHeaderTextColor(x Color) Option {
+	return func(p *Prompt) error {
+		p.renderer.groupHeaderTextColor = x
+		return nil
+	}
+}
+
+// OptionGroupHeaderBGColor to change a background color of group headers in drop down suggestions.
+func OptionGroupHeaderBGColor(x Color) Option {
+	return func(p *Prompt) error {
+		p.renderer.groupHeaderBGColor = x
+		return nil
+	}
+}
+
 // OptionMaxSuggestion specify the max number of displayed suggestions.
 func OptionMaxSuggestion(x uint16) Option {
 	return func(p *Prompt) error {
@@ -197,6 +226,40 @@ func OptionMaxSuggestion(x uint16) Option {
 	}
 }
 
+// OptionAsyncCompleter to run a completer in background instead of the completer passed to New,
+// so that a slow completer doesn't freeze typing. It is called after the document is left unchanged
+// for the debounce duration, and its context is canceled when the document is changed.
+// A loading indicator is shown in the drop down suggestions meanwhile.
+func OptionAsyncCompleter(fn CompleterContext, debounce time.Duration) Option {
+	return func(p *Prompt) error {
+		p.completion.asyncCompleter = streamOnce(fn)
+		p.completion.debounce = debounce
+		p.completion.asyncCh = make(chan asyncSuggestions)
+		return nil
+	}
+}
+
+// OptionStreamingCompleter to run a completer which sends suggestions gradually in background
+// instead of the completer passed to New. Each batch is appended to the drop down suggestions
+// as it arrives while keeping the selected item. See OptionAsyncCompleter for the debounce duration.
+func OptionStreamingCompleter(fn StreamingCompleter, debounce time.Duration) Option {
+	return func(p *Prompt) error {
+		p.completion.asyncCompleter = fn
+		p.completion.debounce = debounce
+		p.completion.asyncCh = make(chan asyncSuggestions)
+		return nil
+	}
+}
+
+// OptionCompletionLayout to switch the layout of drop down suggestions. The default is ListLayout.
+// In GridLayout, Left/Right keys move between columns and PageUp/PageDown keys move between pages.
+func OptionCompletionLayout(x CompletionLayout) Option {
+	return func(p *Prompt) error {
+		p.completion.layout = x
+		return nil
+	}
+}
+
 // OptionHistory to set history expressed by string array.
 func OptionHistory(x []string) Option {
 	return func(p *Prompt) error {
@@ -222,6 +285,23 @@ func OptionCompletionOnDown() Option {
 	}
 }
 
+// OptionCompletionStyle to switch the behavior of Tab key. The default is MenuCompletion.
+func OptionCompletionStyle(x CompletionStyle) Option {
+	return func(p *Prompt) error {
+		p.completionStyle = x
+		return nil
+	}
+}
+
+// OptionAcceptHook to set a hook called when the user accepts a suggestion.
+// It is useful to learn which suggestions are used, e.g. by completer.Frecency.
+func OptionAcceptHook(fn AcceptHook) Option {
+	return func(p *Prompt) error {
+		p.acceptHook = fn
+		return nil
+	}
+}
+
 // SwitchKeyBindMode to set a key bind mode.
 // Deprecated: Please use OptionSwitchKeyBindMode.
 var SwitchKeyBindMode = OptionSwitchKeyBindMode
@@ -283,6 +363,53 @@ func OptionSetStatementTerminator(fn StatementTerminatorCb) Option {
 	}
 }
 
+// OptionExecutorContext to set an executor which receives a context.Context.
+// The context is canceled by Ctrl+c while executing and then the prompt resumes.
+// When it is set, the executor passed to New is not called.
+func OptionExecutorContext(fn ExecutorContext) Option {
+	return func(p *Prompt) error {
+		p.executorContext = fn
+		return nil
+	}
+}
+
+// OptionExecutorErrorHandler to set a callback to report an error returned from ExecutorContext.
+func OptionExecutorErrorHandler(fn ExecutorErrorHandler) Option {
+	return func(p *Prompt) error {
+		p.executorErrorHandler = fn
+		return nil
+	}
+}
+
+// OptionPreExec to set a hook called before the executor runs.
+// The hook can rewrite the input or skip executing it.
+func OptionPreExec(fn PreExecHook) Option {
+	return func(p *Prompt) error {
+		p.preExecHook = fn
+		return nil
+	}
+}
+
+// OptionPostExec to set a hook called after the executor returns with the time taken and the error.
+func OptionPostExec(fn PostExecHook) Option {
+	return func(p *Prompt) error {
+		p.postExecHook = fn
+		return nil
+	}
+}
+
+// OptionAsyncExecutor runs the executor in background as a Job, so that the prompt
+// accepts the next input immediately. The executor should print its output through
+// WriterFromContext with ExecutorContext, or Prompt.Printf and Prompt.Writer with Executor,
+// because writing to os.Stdout directly garbles the prompt. The running jobs can be listed
+// by Prompt.Jobs and canceled by Prompt.CancelJob, and PostExecHook is called inside the loop.
+func OptionAsyncExecutor() Option {
+	return func(p *Prompt) error {
+		p.asyncExecutor = true
+		return nil
+	}
+}
+
 // New returns a Prompt with powerful auto-completion.
 func New(executor Executor, completer Completer, opts ...Option) *Prompt {
 	defaultWriter := NewStdoutWriter()
@@ -310,6 +437,8 @@ func New(executor Executor, completer Completer, opts ...Option) *Prompt {
 			selectedDescriptionBGColor:   Cyan,
 			scrollbarThumbColor:          DarkGray,
 			scrollbarBGColor:             Cyan,
+			groupHeaderTextColor:         White,
+			groupHeaderBGColor:           DarkGray,
 		},
 		buf:         NewBuffer(),
 		executor:    executor,
@@ -317,6 +446,9 @@ func New(executor Executor, completer Completer, opts ...Option) *Prompt {
 		lexer:       NewLexer(),
 		completion:  NewCompletionManager(completer, 6),
 		keyBindMode: EmacsKeyBind, // All the above assume that bash is running in the default Emacs setting
+		actionCh:    make(chan action, 32),
+		outputCh:    make(chan struct{}, 1),
+		jobDoneCh:   make(chan struct{}, 1),
 	}
 
 	for _, opt := range opts {
@@ -2,7 +2,12 @@ package prompt
 
 import (
 	"bytes"
+	"fmt"
+	"io"
 	"os"
+	"runtime"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/c-bata/go-prompt/internal/debug"
@@ -31,6 +36,10 @@ type Prompt struct {
 	prevText              string
 	renderer              *Render
 	executor              Executor
+	executorContext       ExecutorContext
+	executorErrorHandler  ExecutorErrorHandler
+	preExecHook           PreExecHook
+	postExecHook          PostExecHook
 	history               *History
 	lexer                 *Lexer
 	completion            *CompletionManager
@@ -38,9 +47,30 @@ type Prompt struct {
 	ASCIICodeBindings     []ASCIICodeBind
 	keyBindMode           KeyBindMode
 	completionOnDown      bool
+	completionStyle       CompletionStyle
+	tabbed                bool // The previous key was Tab which did not open the menu.
+	acceptHook            AcceptHook
 	exitChecker           ExitChecker
 	statementTerminatorCb StatementTerminatorCb
 	skipTearDown          bool
+	actionCh              chan action
+	asyncExecutor         bool
+
+	jobsMu    sync.Mutex
+	jobs      map[int]*Job
+	lastJobID int
+	// While Run is running, finished jobs queue their results in jobResults
+	// and notify the loop by jobDoneCh, which calls PostExecHook for them.
+	jobsLoop   bool
+	jobResults []jobResult
+	jobDoneCh  chan struct{}
+
+	// While the Run/Input loop is rendering, Printf and Writer queue data in output
+	// and notify the loop by outputCh, so they never block the loop and its callbacks.
+	outputMu  sync.Mutex
+	rendering bool
+	output    [][]byte
+	outputCh  chan struct{}
 }
 
 // Exec is the struct contains user input context.
@@ -53,13 +83,94 @@ func (p *Prompt) ClearScreen() {
 	p.renderer.ClearScreen()
 }
 
+// Printf formats according to a format specifier and prints it above the prompt.
+// It is safe to call from any goroutine. See Writer for more details.
+func (p *Prompt) Printf(format string, a ...interface{}) {
+	_, _ = fmt.Fprintf(p.Writer(), format, a...)
+}
+
+// Writer returns io.Writer which prints above the prompt.
+// It is safe to use from any goroutine, including a Completer, a key binding or a hook
+// called by the Run/Input loop. While the loop is running, the data is queued and the loop
+// erases the rendered prompt, prints the data and then redraws the prompt with the buffer
+// and cursor intact. Otherwise, the data is written as it is.
+func (p *Prompt) Writer() io.Writer {
+	return &promptWriter{p: p}
+}
+
+type promptWriter struct {
+	p *Prompt
+}
+
+func (w *promptWriter) Write(data []byte) (int, error) {
+	p := w.p
+	p.outputMu.Lock()
+	defer p.outputMu.Unlock()
+
+	if !p.rendering {
+		p.renderer.out.WriteRaw(data)
+		return len(data), p.renderer.out.Flush()
+	}
+
+	// Copy data because the caller may reuse it after Write returns.
+	p.output = append(p.output, append([]byte(nil), data...))
+	select {
+	case p.outputCh <- struct{}{}:
+	default:
+	}
+	return len(data), nil
+}
+
+// setRendering switches whether Printf and Writer queue data for the Run/Input loop.
+// Data which is still queued is written as it is when the loop stops rendering.
+func (p *Prompt) setRendering(rendering bool) {
+	p.outputMu.Lock()
+	defer p.outputMu.Unlock()
+
+	p.rendering = rendering
+	if rendering || len(p.output) == 0 {
+		return
+	}
+	for _, data := range p.output {
+		p.renderer.out.WriteRaw(data)
+	}
+	p.output = nil
+	debug.AssertNoError(p.renderer.out.Flush())
+}
+
+// printOutput prints the data queued by Printf and Writer above the prompt,
+// and then redraws the prompt with the buffer and cursor intact.
+func (p *Prompt) printOutput() {
+	p.outputMu.Lock()
+	output := p.output
+	p.output = nil
+	p.outputMu.Unlock()
+	if len(output) == 0 {
+		return
+	}
+
+	p.renderer.Erase(p.buf)
+	for _, data := range output {
+		p.renderer.out.WriteRaw(data)
+		if !bytes.HasSuffix(data, []byte{'\n'}) {
+			p.renderer.out.WriteRaw([]byte{'\n'})
+		}
+	}
+	p.renderer.Render(p.buf, "", p.completion, p.lexer)
+}
+
 // Run starts prompt.
 func (p *Prompt) Run() {
 	p.skipTearDown = false
 	defer debug.Teardown()
 	debug.Log("start prompt")
+	p.setRendering(true)
+	defer p.setRendering(false)
+	defer p.completion.cancelAsync()
 	p.setUp()
 	defer p.tearDown()
+	p.enterJobsLoop()
+	defer p.exitJobsLoop()
 
 	if p.completion.showAtStart {
 		p.completion.Update(*p.buf.Document())
@@ -73,52 +184,112 @@ func (p *Prompt) Run() {
 
 	exitCh := make(chan int)
 	winSizeCh := make(chan *WinSize)
+	suspendCh := make(chan struct{})
 	stopHandleSignalCh := make(chan struct{})
-	go p.handleSignals(exitCh, winSizeCh, stopHandleSignalCh)
+	go p.handleSignals(exitCh, winSizeCh, suspendCh, stopHandleSignalCh)
 
-	for {
-		select {
-		case b := <-bufCh:
-			if shouldExit, e := p.feed(b); shouldExit {
-				p.renderer.BreakLine(p.buf, p.lexer)
-				stopReadBufCh <- struct{}{}
-				stopHandleSignalCh <- struct{}{}
-				return
-			} else if e != nil {
-				// Stop goroutine to run readBuffer function
-				stopReadBufCh <- struct{}{}
-				stopHandleSignalCh <- struct{}{}
+	suspend := func() {
+		stopReadBufCh <- struct{}{}
+		stopHandleSignalCh <- struct{}{}
 
-				// Unset raw mode
-				// Reset to Blocking mode because returned EAGAIN when still set non-blocking mode.
-				debug.AssertNoError(p.in.TearDown())
-				p.executor(e.input)
+		p.renderer.Erase(p.buf)
+		debug.AssertNoError(p.in.TearDown())
+		suspendProcess()
 
-				p.completion.Update(*p.buf.Document())
+		// The terminal may have been used by other programs while suspended.
+		debug.AssertNoError(p.in.Setup())
+		p.updateWinSize(p.in.GetWinSize())
+		p.renderer.Render(p.buf, "", p.completion, p.lexer)
 
-				p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
+		go p.readBuffer(bufCh, stopReadBufCh)
+		go p.handleSignals(exitCh, winSizeCh, suspendCh, stopHandleSignalCh)
+	}
 
-				if p.exitChecker != nil && p.exitChecker(e.input, true) {
-					p.skipTearDown = true
-					return
-				}
-				// Set raw mode
-				debug.AssertNoError(p.in.Setup())
-				go p.readBuffer(bufCh, stopReadBufCh)
-				go p.handleSignals(exitCh, winSizeCh, stopHandleSignalCh)
-			} else {
-				p.completion.Update(*p.buf.Document())
+	for {
+		var (
+			shouldExit bool
+			e          *Exec
+		)
+		select {
+		case b := <-bufCh:
+			if GetKey(b) == ControlZ && runtime.GOOS != "windows" {
+				// Raw mode disables ISIG, so emulate the terminal driver.
+				suspend()
+				continue
+			}
+			shouldExit, e = p.feed(b)
+		case a := <-p.actionCh:
+			shouldExit, e = p.apply(a)
+		case r := <-p.completion.asyncCh:
+			if p.completion.applyAsync(r) {
 				p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
 			}
+			continue
 		case w := <-winSizeCh:
-			p.renderer.UpdateWinSize(w)
+			p.updateWinSize(w)
 			p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
+			continue
+		case <-suspendCh:
+			suspend()
+			continue
 		case code := <-exitCh:
 			p.renderer.BreakLine(p.buf, p.lexer)
 			p.tearDown()
 			os.Exit(code)
+		case <-p.jobDoneCh:
+			p.runJobHooks()
+		case <-p.outputCh:
+			p.printOutput()
+			continue
 		default:
 			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+
+		e = p.preExec(e)
+		if shouldExit {
+			p.renderer.BreakLine(p.buf, p.lexer)
+			stopReadBufCh <- struct{}{}
+			stopHandleSignalCh <- struct{}{}
+			return
+		} else if e != nil && p.asyncExecutor {
+			p.startJob(e.input)
+
+			if p.exitChecker != nil && p.exitChecker(e.input, true) {
+				stopReadBufCh <- struct{}{}
+				stopHandleSignalCh <- struct{}{}
+				return
+			}
+			p.completion.Update(*p.buf.Document())
+			p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
+		} else if e != nil {
+			// Stop goroutine to run readBuffer function
+			stopReadBufCh <- struct{}{}
+			stopHandleSignalCh <- struct{}{}
+
+			// Unset raw mode
+			// Reset to Blocking mode because returned EAGAIN when still set non-blocking mode.
+			debug.AssertNoError(p.in.TearDown())
+			p.printOutput()
+			p.setRendering(false)
+			p.execute(e.input)
+			p.setRendering(true)
+
+			p.completion.Update(*p.buf.Document())
+
+			p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
+
+			if p.exitChecker != nil && p.exitChecker(e.input, true) {
+				p.skipTearDown = true
+				return
+			}
+			// Set raw mode
+			debug.AssertNoError(p.in.Setup())
+			go p.readBuffer(bufCh, stopReadBufCh)
+			go p.handleSignals(exitCh, winSizeCh, suspendCh, stopHandleSignalCh)
+		} else {
+			p.completion.Update(*p.buf.Document())
+			p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
 		}
 	}
 }
@@ -130,19 +301,16 @@ func (p *Prompt) feed(b []byte) (shouldExit bool, exec *Exec) {
 	p.buf.lastKeyStroke = key
 	// completion
 	completing := p.completion.Completing()
-	p.handleCompletionKeyBinding(key, completing)
+	if p.handleCompletionKeyBinding(key, completing) {
+		return
+	}
 
 	switch key {
 	case Enter, ControlJ, ControlM:
 		if p.statementTerminatorCb == nil || !p.statementTerminatorCb(p.buf.lastKeyStroke, p.buf) {
 			p.buf.NewLine(false)
 		} else {
-			p.renderer.BreakLine(p.buf, p.lexer)
-			exec = &Exec{input: p.buf.Text()}
-			p.buf = NewBuffer()
-			if exec.input != "" {
-				p.history.Add(exec.input)
-			}
+			exec = p.submit()
 		}
 	case ControlC:
 		p.renderer.BreakLine(p.buf, p.lexer)
@@ -198,13 +366,53 @@ func (p *Prompt) feed(b []byte) (shouldExit bool, exec *Exec) {
 	return
 }
 
-func (p *Prompt) handleCompletionKeyBinding(key Key, completing bool) {
+func (p *Prompt) submit() (exec *Exec) {
+	p.renderer.BreakLine(p.buf, p.lexer)
+	exec = &Exec{input: p.buf.Text()}
+	p.buf = NewBuffer()
+	if exec.input != "" {
+		p.history.Add(exec.input)
+	}
+	return exec
+}
+
+// handleCompletionKeyBinding returns true if the key is consumed by the completion.
+func (p *Prompt) handleCompletionKeyBinding(key Key, completing bool) bool {
+	tabbed := p.tabbed
+	p.tabbed = false
+
+	if p.completion.layout == GridLayout && completing {
+		switch key {
+		case Right:
+			p.completion.NextColumn()
+			return true
+		case Left:
+			p.completion.PreviousColumn()
+			return true
+		case PageDown:
+			p.completion.NextPage()
+			return true
+		case PageUp:
+			p.completion.PreviousPage()
+			return true
+		}
+	}
+
 	switch key {
 	case Down:
 		if completing || p.completionOnDown {
 			p.completion.Next()
 		}
 	case Tab, ControlI:
+		// With CommonPrefixCompletion, Tab inserts the common prefix, and the second one in a row opens the menu.
+		if p.completionStyle == CommonPrefixCompletion && !completing {
+			// Accepting the sole suggestion finishes the completion, so the next Tab starts over.
+			sole := len(p.completion.GetSuggestions()) == 1
+			if p.completeCommonPrefix() || !tabbed {
+				p.tabbed = !sole
+				return false
+			}
+		}
 		p.completion.Next()
 	case Up:
 		if completing {
@@ -214,14 +422,64 @@ func (p *Prompt) handleCompletionKeyBinding(key Key, completing bool) {
 		p.completion.Previous()
 	default:
 		if s, ok := p.completion.GetSelectedSuggestion(); ok {
-			w := p.buf.Document().GetWordBeforeCursorUntilSeparator(p.completion.wordSeparator)
-			if w != "" {
-				p.buf.DeleteBeforeCursor(len([]rune(w)))
-			}
-			p.buf.InsertText(s.Text, false, true)
+			p.acceptSuggestion(s)
 		}
 		p.completion.Reset()
 	}
+	return false
+}
+
+// completeCommonPrefix inserts the longest common prefix of suggestions,
+// or the suggestion itself if it is the only one. It returns false if nothing is inserted.
+func (p *Prompt) completeCommonPrefix() bool {
+	suggests := p.completion.GetSuggestions()
+	switch len(suggests) {
+	case 0:
+		return false
+	case 1:
+		p.acceptSuggestion(suggests[0])
+		return true
+	}
+
+	prefix := commonPrefix(suggests)
+	d := p.buf.Document()
+	before, _ := p.completion.replaceRange(d, suggests[0])
+	textBeforeCursor := []rune(d.TextBeforeCursor())
+	word := string(textBeforeCursor[len(textBeforeCursor)-before:])
+	if p.completion.shellQuoting && suggests[0].Range == nil {
+		word, _ = d.ShellWordBeforeCursor()
+	}
+	if len([]rune(prefix)) <= len([]rune(word)) || !strings.HasPrefix(strings.ToLower(prefix), strings.ToLower(word)) {
+		return false
+	}
+	p.insertSuggestion(Suggest{Text: prefix, Range: suggests[0].Range})
+	return true
+}
+
+// acceptSuggestion inserts the suggestion chosen by the user, and tells it to the accept hook.
+func (p *Prompt) acceptSuggestion(s Suggest) {
+	if p.acceptHook != nil {
+		p.acceptHook(*p.buf.Document(), s)
+	}
+	p.insertSuggestion(s)
+}
+
+func (p *Prompt) insertSuggestion(s Suggest) {
+	text := p.completion.insertedText(p.buf.Document(), s)
+	before, after := p.completion.replaceRange(p.buf.Document(), s)
+	if after > 0 {
+		p.buf.Delete(after)
+	}
+	if before > 0 {
+		p.buf.DeleteBeforeCursor(before)
+	}
+	p.buf.InsertText(text+s.AppendText, false, true)
+
+	if s.CursorOffset < 0 {
+		p.buf.CursorLeft(-s.CursorOffset)
+	} else if s.CursorOffset > 0 {
+		p.buf.CursorRight(s.CursorOffset)
+	}
 }
 
 func (p *Prompt) handleKeyBinding(key Key) bool {
@@ -270,6 +528,9 @@ func (p *Prompt) handleASCIICodeBinding(b []byte) bool {
 func (p *Prompt) Input() string {
 	defer debug.Teardown()
 	debug.Log("start prompt")
+	p.setRendering(true)
+	defer p.setRendering(false)
+	defer p.completion.cancelAsync()
 	p.setUp()
 	defer p.tearDown()
 
@@ -283,22 +544,39 @@ func (p *Prompt) Input() string {
 	go p.readBuffer(bufCh, stopReadBufCh)
 
 	for {
+		var (
+			shouldExit bool
+			e          *Exec
+		)
 		select {
 		case b := <-bufCh:
-			if shouldExit, e := p.feed(b); shouldExit {
-				p.renderer.BreakLine(p.buf, p.lexer)
-				stopReadBufCh <- struct{}{}
-				return ""
-			} else if e != nil {
-				// Stop goroutine to run readBuffer function
-				stopReadBufCh <- struct{}{}
-				return e.input
-			} else {
-				p.completion.Update(*p.buf.Document())
+			shouldExit, e = p.feed(b)
+		case a := <-p.actionCh:
+			shouldExit, e = p.apply(a)
+		case r := <-p.completion.asyncCh:
+			if p.completion.applyAsync(r) {
 				p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
 			}
+			continue
+		case <-p.outputCh:
+			p.printOutput()
+			continue
 		default:
 			time.Sleep(10 * time.Millisecond)
+			continue
+		}
+
+		if shouldExit {
+			p.renderer.BreakLine(p.buf, p.lexer)
+			stopReadBufCh <- struct{}{}
+			return ""
+		} else if e != nil {
+			// Stop goroutine to run readBuffer function
+			stopReadBufCh <- struct{}{}
+			return e.input
+		} else {
+			p.completion.Update(*p.buf.Document())
+			p.renderer.Render(p.buf, p.prevText, p.completion, p.lexer)
 		}
 	}
 }
@@ -319,10 +597,16 @@ func (p *Prompt) readBuffer(bufCh chan []byte, stopCh chan struct{}) {
 	}
 }
 
+// updateWinSize tells the window size to the renderer and the completion which lays out the grid by it.
+func (p *Prompt) updateWinSize(ws *WinSize) {
+	p.renderer.UpdateWinSize(ws)
+	p.completion.setWidth(ws.Col)
+}
+
 func (p *Prompt) setUp() {
 	debug.AssertNoError(p.in.Setup())
 	p.renderer.Setup()
-	p.renderer.UpdateWinSize(p.in.GetWinSize())
+	p.updateWinSize(p.in.GetWinSize())
 }
 
 func (p *Prompt) tearDown() {
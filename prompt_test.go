@@ -0,0 +1,265 @@
+package prompt
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testParser struct{}
+
+func (*testParser) Setup() error    { return nil }
+func (*testParser) TearDown() error { return nil }
+func (*testParser) GetWinSize() *WinSize {
+	return &WinSize{Row: 24, Col: 80}
+}
+func (*testParser) Read() ([]byte, error) { return nil, errors.New("EAGAIN") }
+
+type testWriter struct {
+	VT100Writer
+	flushed []byte
+}
+
+func (w *testWriter) Flush() error {
+	w.flushed = append(w.flushed, w.buffer...)
+	w.buffer = []byte{}
+	return nil
+}
+
+func newTestPrompt(w ConsoleWriter) *Prompt {
+	return &Prompt{
+		in: &testParser{},
+		renderer: &Render{
+			prefix:             "> ",
+			out:                w,
+			livePrefixCallback: func() (string, bool) { return "", false },
+			row:                24,
+			col:                80,
+		},
+		buf:        NewBuffer(),
+		history:    NewHistory(),
+		lexer:      NewLexer(),
+		completion: NewCompletionManager(func(Document) []Suggest { return nil }, 6),
+		actionCh:   make(chan action, 32),
+		outputCh:   make(chan struct{}, 1),
+		jobDoneCh:  make(chan struct{}, 1),
+	}
+}
+
+func TestPromptWriter(t *testing.T) {
+	w := &testWriter{}
+	p := newTestPrompt(w)
+	p.Printf("hello %s\n", "world")
+	if got := string(w.flushed); got != "hello world\n" {
+		t.Errorf("Should be %q, but got %q", "hello world\n", got)
+	}
+
+	w.flushed = nil
+	p.buf.InsertText("input", false, true)
+	p.setRendering(true)
+	p.Printf("notification")
+	if len(w.flushed) != 0 {
+		t.Errorf("Should be queued while rendering, but got %q", w.flushed)
+	}
+	p.printOutput()
+
+	got := string(w.flushed)
+	msg := strings.Index(got, "notification\n")
+	input := strings.LastIndex(got, "input")
+	if msg == -1 || input == -1 || input < msg {
+		t.Errorf("The prompt should be redrawn after the message, but got %q", got)
+	}
+	if p.buf.Text() != "input" {
+		t.Errorf("Buffer should be kept, but got %q", p.buf.Text())
+	}
+}
+
+func TestPromptWriterInLoop(t *testing.T) {
+	w := &testWriter{}
+	p := newTestPrompt(w)
+	p.completion.completer = func(d Document) []Suggest {
+		p.Printf("completing %q", d.Text)
+		return nil
+	}
+	p.InsertText("a")
+	p.Submit()
+
+	done := make(chan string)
+	go func() { done <- p.Input() }()
+	select {
+	case got := <-done:
+		if got != "a" {
+			t.Errorf("Should be %q, but got %q", "a", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Printf from the completer should not block the loop")
+	}
+	if expected := `completing "a"`; !strings.Contains(string(w.flushed), expected) {
+		t.Errorf("Should contain %q, but got %q", expected, w.flushed)
+	}
+}
+
+func TestAsyncCompletionCanceledOnExit(t *testing.T) {
+	p := newTestPrompt(&testWriter{})
+	started := make(chan context.Context, 1)
+	p.completion.asyncCompleter = func(ctx context.Context, d Document) <-chan []Suggest {
+		started <- ctx
+		return make(chan []Suggest)
+	}
+	p.completion.asyncCh = make(chan asyncSuggestions)
+	p.InsertText("a")
+	p.Stop()
+	p.Input()
+
+	ctx := <-started
+	select {
+	case <-ctx.Done():
+	case <-time.After(3 * time.Second):
+		t.Fatal("The completer should be canceled when the loop exits")
+	}
+}
+
+func TestInsertSuggestion(t *testing.T) {
+	scenarioTable := []struct {
+		name     string
+		text     string
+		cursor   int
+		suggest  Suggest
+		expected string
+		exCursor int
+	}{
+		{
+			name:     "replace the word before the cursor",
+			text:     "git che",
+			cursor:   7,
+			suggest:  Suggest{Text: "checkout"},
+			expected: "git checkout",
+			exCursor: 12,
+		},
+		{
+			name:     "replace the range and append text",
+			text:     "cd foo/ba/",
+			cursor:   9,
+			suggest:  Suggest{Text: "bar", AppendText: "/", Range: &SuggestRange{Start: -2, End: 1}},
+			expected: "cd foo/bar/",
+			exCursor: 11,
+		},
+		{
+			name:     "move the cursor after insertion",
+			text:     "pri",
+			cursor:   3,
+			suggest:  Suggest{Text: "print()", CursorOffset: -1},
+			expected: "print()",
+			exCursor: 6,
+		},
+	}
+
+	for _, s := range scenarioTable {
+		t.Run(s.name, func(t *testing.T) {
+			p := newTestPrompt(&testWriter{})
+			p.buf.InsertText(s.text, false, false)
+			p.buf.cursorPosition = s.cursor
+			p.insertSuggestion(s.suggest)
+			if p.buf.Text() != s.expected {
+				t.Errorf("Should be %q, but got %q", s.expected, p.buf.Text())
+			}
+			if p.buf.cursorPosition != s.exCursor {
+				t.Errorf("Should be %d, but got %d", s.exCursor, p.buf.cursorPosition)
+			}
+		})
+	}
+}
+
+func TestCommonPrefixCompletion(t *testing.T) {
+	p := newTestPrompt(&testWriter{})
+	p.completionStyle = CommonPrefixCompletion
+	p.completion.completer = func(d Document) []Suggest {
+		return FilterHasPrefix([]Suggest{
+			{Text: "checkout"},
+			{Text: "cherry-pick"},
+			{Text: "commit", AppendText: " "},
+		}, d.GetWordBeforeCursor(), true)
+	}
+
+	p.buf.InsertText("git ch", false, true)
+	p.completion.Update(*p.buf.Document())
+	p.handleCompletionKeyBinding(Tab, false)
+	if p.buf.Text() != "git che" || p.completion.Completing() {
+		t.Errorf("Common prefix should be inserted, but got %q", p.buf.Text())
+	}
+
+	p.completion.Update(*p.buf.Document())
+	p.handleCompletionKeyBinding(Tab, false)
+	if !p.completion.Completing() {
+		t.Errorf("Second Tab should select a suggestion")
+	}
+
+	p.buf = NewBuffer()
+	p.completion.Reset()
+	p.buf.InsertText("git co", false, true)
+	p.completion.Update(*p.buf.Document())
+	p.handleCompletionKeyBinding(Tab, false)
+	if p.buf.Text() != "git commit " {
+		t.Errorf("Sole suggestion should be accepted, but got %q", p.buf.Text())
+	}
+
+	// There is nothing to insert for "checkout" and "cherry-pick" after "git che".
+	p.buf = NewBuffer()
+	p.completion.Reset()
+	p.buf.InsertText("git che", false, true)
+	p.completion.Update(*p.buf.Document())
+	p.handleCompletionKeyBinding(Tab, false)
+	if p.buf.Text() != "git che" || p.completion.Completing() {
+		t.Errorf("First Tab should not open the menu, but got %q", p.buf.Text())
+	}
+	p.handleCompletionKeyBinding(Tab, false)
+	if !p.completion.Completing() {
+		t.Errorf("Second Tab should select a suggestion")
+	}
+
+	// Other keys between Tabs start over.
+	p.completion.Reset()
+	p.handleCompletionKeyBinding(Tab, false)
+	p.handleCompletionKeyBinding(Left, false)
+	p.handleCompletionKeyBinding(Tab, false)
+	if p.completion.Completing() {
+		t.Errorf("Tab after another key should not open the menu")
+	}
+}
+
+func TestAcceptHook(t *testing.T) {
+	var accepted []string
+	p := newTestPrompt(&testWriter{})
+	p.completionStyle = CommonPrefixCompletion
+	p.acceptHook = func(d Document, s Suggest) {
+		accepted = append(accepted, d.Text+"|"+s.Text)
+	}
+	p.completion.completer = func(d Document) []Suggest {
+		return FilterHasPrefix([]Suggest{{Text: "checkout"}, {Text: "cherry-pick"}}, d.GetWordBeforeCursor(), true)
+	}
+
+	p.buf.InsertText("git c", false, true)
+	p.completion.Update(*p.buf.Document())
+	p.handleCompletionKeyBinding(Tab, false) // Inserting the common prefix is not accepting.
+	p.completion.Update(*p.buf.Document())
+	p.handleCompletionKeyBinding(Tab, false)
+	p.handleCompletionKeyBinding(ControlM, true)
+
+	expected := []string{"git che|checkout"}
+	if !reflect.DeepEqual(accepted, expected) {
+		t.Errorf("Should be %#v, but got %#v", expected, accepted)
+	}
+}
+
+func TestInsertSuggestionWithShellQuoting(t *testing.T) {
+	p := newTestPrompt(&testWriter{})
+	p.completion.shellQuoting = true
+	p.buf.InsertText(`cat "my fi`, false, true)
+	p.insertSuggestion(Suggest{Text: "my file.txt", AppendText: " "})
+	if expected := `cat "my file.txt" `; p.buf.Text() != expected {
+		t.Errorf("Should be %q, but got %q", expected, p.buf.Text())
+	}
+}
@@ -38,6 +38,8 @@ type Render struct {
 	selectedDescriptionBGColor   Color
 	scrollbarThumbColor          Color
 	scrollbarBGColor             Color
+	groupHeaderTextColor         Color
+	groupHeaderBGColor           Color
 }
 
 // Setup to initialize console output.
@@ -92,9 +94,52 @@ func (r *Render) renderWindowTooSmall() {
 	r.out.WriteStr("Your console window is too small...")
 }
 
+func (r *Render) renderLoadingIndicator(buf *Buffer) {
+	prefix := r.getCurrentPrefix()
+	formatted, width := formatTexts(
+		[]string{loadingIndicator},
+		int(r.col)-runewidth.StringWidth(prefix),
+		leftPrefix,
+		leftSuffix,
+	)
+	if width == 0 {
+		return
+	}
+	r.prepareArea(1)
+
+	cursor := runewidth.StringWidth(prefix) + runewidth.StringWidth(buf.Document().TextBeforeCursor())
+	x, _ := r.toPos(cursor)
+	if x+width >= int(r.col) {
+		cursor = r.backward(cursor, x+width-int(r.col))
+	}
+
+	r.out.CursorDown(1)
+	r.out.SetColor(r.descriptionTextColor, r.descriptionBGColor, false)
+	r.out.WriteStr(formatted[0])
+	r.out.SetColor(DefaultColor, DefaultColor, false)
+	r.lineWrap(cursor + width)
+	r.backward(cursor+width, width)
+
+	if x+width >= int(r.col) {
+		r.out.CursorForward(x + width - int(r.col))
+	}
+	r.out.CursorUp(1)
+	r.out.SetColor(DefaultColor, DefaultColor, false)
+}
+
 func (r *Render) renderCompletion(buf *Buffer, completions *CompletionManager) {
-	suggestions := completions.GetSuggestions()
-	if len(completions.GetSuggestions()) == 0 {
+	if completions.Loading() {
+		// Suggestions for the previous document might not match the current one.
+		r.renderLoadingIndicator(buf)
+		return
+	}
+	if completions.layout == GridLayout {
+		r.renderCompletionGrid(buf, completions)
+		return
+	}
+	// Rows include the headers of groups.
+	suggestions := completions.tmp
+	if len(suggestions) == 0 {
 		return
 	}
 	prefix := r.getCurrentPrefix()
@@ -134,27 +179,33 @@ func (r *Render) renderCompletion(buf *Buffer, completions *CompletionManager) {
 	r.out.SetColor(White, Cyan, false)
 	for i := 0; i < windowHeight; i++ {
 		r.out.CursorDown(1)
+		style := formatted[i].Style
+		fg, bg, bold := r.suggestionTextColor, r.suggestionBGColor, false
+		if suggestions[completions.verticalScroll+i].groupHeader {
+			r.out.SetColor(r.groupHeaderTextColor, r.groupHeaderBGColor, true)
+			r.out.WriteStr(formatted[i].Text + formatted[i].Description)
+			r.renderScrollbar(isScrollThumb(i))
+			r.lineWrap(cursor + width)
+			r.backward(cursor+width, width)
+			continue
+		}
 		if i == selected {
-			r.out.SetColor(r.selectedSuggestionTextColor, r.selectedSuggestionBGColor, true)
-		} else {
-			r.out.SetColor(r.suggestionTextColor, r.suggestionBGColor, false)
+			fg, bg, bold = r.selectedSuggestionTextColor, r.selectedSuggestionBGColor, true
+		} else if style != nil {
+			fg, bg = style.TextColor, style.BGColor
 		}
-		r.out.WriteStr(formatted[i].Text)
+		r.out.SetColor(fg, bg, bold)
+		r.renderSuggestionText(formatted[i].Text, &suggestions[completions.verticalScroll+i], buf.Document(), completions, fg, bg, bold)
 
 		if i == selected {
 			r.out.SetColor(r.selectedDescriptionTextColor, r.selectedDescriptionBGColor, false)
+		} else if style != nil {
+			r.out.SetColor(style.DescriptionTextColor, style.DescriptionBGColor, false)
 		} else {
 			r.out.SetColor(r.descriptionTextColor, r.descriptionBGColor, false)
 		}
 		r.out.WriteStr(formatted[i].Description)
-
-		if isScrollThumb(i) {
-			r.out.SetColor(DefaultColor, r.scrollbarThumbColor, false)
-		} else {
-			r.out.SetColor(DefaultColor, r.scrollbarBGColor, false)
-		}
-		r.out.WriteStr(" ")
-		r.out.SetColor(DefaultColor, DefaultColor, false)
+		r.renderScrollbar(isScrollThumb(i))
 
 		r.lineWrap(cursor + width)
 		r.backward(cursor+width, width)
@@ -168,6 +219,108 @@ func (r *Render) renderCompletion(buf *Buffer, completions *CompletionManager) {
 	r.out.SetColor(DefaultColor, DefaultColor, false)
 }
 
+func (r *Render) renderScrollbar(thumb bool) {
+	if thumb {
+		r.out.SetColor(DefaultColor, r.scrollbarThumbColor, false)
+	} else {
+		r.out.SetColor(DefaultColor, r.scrollbarBGColor, false)
+	}
+	r.out.WriteStr(" ")
+	r.out.SetColor(DefaultColor, DefaultColor, false)
+}
+
+func (r *Render) renderCompletionGrid(buf *Buffer, completions *CompletionManager) {
+	suggestions := completions.GetSuggestions()
+	if len(suggestions) == 0 {
+		return
+	}
+	// -1 to avoid the line wrap at the last column.
+	maxWidth := int(r.col) - 1
+
+	texts := make([]string, len(suggestions))
+	for i := range suggestions {
+		texts[i] = suggestions[i].displayText()
+	}
+	formatted, width := formatTexts(texts, maxWidth, leftPrefix, leftSuffix)
+	if width == 0 {
+		return
+	}
+	start, end, rows := completions.gridPage()
+
+	var footer string
+	if s, ok := completions.GetSelectedSuggestion(); ok && s.Description != "" {
+		if f, w := formatTexts([]string{s.Description}, maxWidth, rightPrefix, rightSuffix); w > 0 {
+			footer = f[0]
+		}
+	}
+	height := rows
+	if footer != "" {
+		height++
+	}
+	r.prepareArea(height)
+
+	cursor := runewidth.StringWidth(r.getCurrentPrefix()) + runewidth.StringWidth(buf.Document().TextBeforeCursor())
+	x, _ := r.toPos(cursor)
+
+	for row := 0; row < rows; row++ {
+		r.out.CursorDown(1)
+		r.out.CursorBackward(int(r.col))
+		for i := start + row; i < end; i += rows {
+			style := suggestions[i].Style
+			fg, bg, bold := r.suggestionTextColor, r.suggestionBGColor, false
+			if i == completions.selected {
+				fg, bg, bold = r.selectedSuggestionTextColor, r.selectedSuggestionBGColor, true
+			} else if style != nil {
+				fg, bg = style.TextColor, style.BGColor
+			}
+			r.out.SetColor(fg, bg, bold)
+			r.renderSuggestionText(formatted[i], &suggestions[i], buf.Document(), completions, fg, bg, bold)
+		}
+		r.out.SetColor(DefaultColor, DefaultColor, false)
+	}
+	if footer != "" {
+		r.out.CursorDown(1)
+		r.out.CursorBackward(int(r.col))
+		r.out.SetColor(r.selectedDescriptionTextColor, r.selectedDescriptionBGColor, false)
+		r.out.WriteStr(footer)
+		r.out.SetColor(DefaultColor, DefaultColor, false)
+	}
+
+	r.out.CursorUp(height)
+	r.out.CursorBackward(int(r.col))
+	r.out.CursorForward(x)
+}
+
+// renderSuggestionText writes the formatted text of the suggestion
+// and highlights the runes which fuzzy match the text replaced by it.
+func (r *Render) renderSuggestionText(formatted string, s *Suggest, d *Document, completions *CompletionManager, fg, bg Color, bold bool) {
+	var positions []int
+	if word := completions.replacedText(d, *s); word != "" && s.DisplayText == "" {
+		_, positions, _ = FuzzyMatch(s.Text, word, true)
+	}
+	if len(positions) == 0 {
+		r.out.WriteStr(formatted)
+		return
+	}
+
+	matched := make(map[int]bool, len(positions))
+	for _, m := range positions {
+		matched[m] = true
+	}
+	original := []rune(s.Text)
+	offset := len([]rune(leftPrefix))
+	for i, c := range []rune(formatted) {
+		// Truncated characters are not highlighted.
+		if j := i - offset; j >= 0 && j < len(original) && matched[j] && original[j] == c {
+			r.out.SetDisplayAttributes(fg, bg, DisplayBold, DisplayUnderline)
+			r.out.WriteStr(string(c))
+			r.out.SetColor(fg, bg, bold)
+			continue
+		}
+		r.out.WriteStr(string(c))
+	}
+}
+
 // ClearScreen :: Clears the screen and moves the cursor to home
 func (r *Render) ClearScreen() {
 	r.out.EraseScreen()
@@ -201,6 +354,10 @@ func (r *Render) Render(buffer *Buffer, previousText string, completion *Complet
 	_, y := r.toPos((traceBackLines + int(r.col)) + cursor)
 
 	h := y + 1 + int(completion.max)
+	if completion.layout == GridLayout {
+		// The footer shows the description of the selected suggestion.
+		h++
+	}
 	if h > int(r.row) || completionMargin > int(r.col) {
 		r.renderWindowTooSmall()
 		return
@@ -231,14 +388,17 @@ func (r *Render) Render(buffer *Buffer, previousText string, completion *Complet
 
 	r.renderCompletion(buffer, completion)
 	if suggest, ok := completion.GetSelectedSuggestion(); ok {
-		cursor = r.backward(cursor, runewidth.StringWidth(buffer.Document().GetWordBeforeCursorUntilSeparator(completion.wordSeparator)))
+		before, after := completion.replaceRange(buffer.Document(), suggest)
+		textBeforeCursor := []rune(buffer.Document().TextBeforeCursor())
+		cursor = r.backward(cursor, runewidth.StringWidth(string(textBeforeCursor[len(textBeforeCursor)-before:])))
 
+		text := completion.insertedText(buffer.Document(), suggest)
 		r.out.SetColor(r.previewSuggestionTextColor, r.previewSuggestionBGColor, false)
-		r.out.WriteStr(suggest.Text)
+		r.out.WriteStr(text)
 		r.out.SetColor(DefaultColor, DefaultColor, false)
-		cursor += runewidth.StringWidth(suggest.Text)
+		cursor += runewidth.StringWidth(text)
 
-		rest := buffer.Document().TextAfterCursor()
+		rest := string([]rune(buffer.Document().TextAfterCursor())[after:])
 
 		if lexer.IsEnabled {
 			processed := lexer.Process(rest)
@@ -257,6 +417,10 @@ func (r *Render) Render(buffer *Buffer, previousText string, completion *Complet
 		}
 
 		r.out.SetColor(DefaultColor, DefaultColor, false)
+		if after > 0 {
+			// Erase the replaced text after the cursor which is already rendered.
+			r.out.EraseEndOfLine()
+		}
 
 		cursor += runewidth.StringWidth(rest)
 		r.lineWrap(cursor)
@@ -345,6 +509,17 @@ func (r *Render) BreakLine(buffer *Buffer, lexer *Lexer) {
 	r.previousCursor = 0
 }
 
+// Erase erases the rendered input and completion menu, and moves the cursor
+// back to the beginning of the prompt. The next Render draws the prompt from scratch.
+func (r *Render) Erase(buffer *Buffer) {
+	if r.col == 0 {
+		return
+	}
+	r.clear(strings.Count(buffer.Text(), "\n")*int(r.col) + r.previousCursor)
+	r.previousCursor = 0
+	debug.AssertNoError(r.out.Flush())
+}
+
 // clear erases the screen from a beginning of input
 // even if there is line break which means input length exceeds a window's width.
 func (r *Render) clear(cursor int) {
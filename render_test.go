@@ -5,6 +5,7 @@ package prompt
 
 import (
 	"reflect"
+	"strings"
 	"syscall"
 	"testing"
 )
@@ -115,3 +116,92 @@ func TestBreakLineCallback(t *testing.T) {
 		t.Errorf("BreakLine callback not called, i should be 3")
 	}
 }
+
+func TestRenderErase(t *testing.T) {
+	r := &Render{
+		prefix: "> ",
+		out: &PosixWriter{
+			fd: syscall.Stdin, // "write" to stdin just so we don't mess with the output of the tests
+		},
+		livePrefixCallback: func() (string, bool) { return "", false },
+		col:                10,
+		previousCursor:     7,
+	}
+	b := NewBuffer()
+	b.InsertText("hello", false, true)
+
+	r.Erase(b)
+	if r.previousCursor != 0 {
+		t.Errorf("previousCursor should be reset to 0, but got %d", r.previousCursor)
+	}
+}
+
+func TestRenderGroupHeader(t *testing.T) {
+	out := &PosixWriter{fd: syscall.Stdin}
+	r := &Render{
+		prefix:               "> ",
+		out:                  out,
+		livePrefixCallback:   func() (string, bool) { return "", false },
+		groupHeaderTextColor: White,
+		groupHeaderBGColor:   DarkGray,
+		col:                  40,
+		row:                  10,
+	}
+	c := NewCompletionManager(func(Document) []Suggest {
+		return []Suggest{{Text: "add", Group: "Commands"}, {Text: "--all", Group: "Flags"}}
+	}, 6)
+	c.Update(Document{})
+
+	r.renderCompletion(NewBuffer(), c)
+	// The header is rendered in bold with its own colors.
+	expected := "\x1b[1;97;100m Commands "
+	if actual := string(out.buffer); !strings.Contains(actual, expected) {
+		t.Errorf("Should contain %q, but got %q", expected, actual)
+	}
+}
+
+func TestRenderMatchedCharacters(t *testing.T) {
+	out := &PosixWriter{fd: syscall.Stdin}
+	r := &Render{
+		prefix:             "> ",
+		out:                out,
+		livePrefixCallback: func() (string, bool) { return "", false },
+		col:                40,
+		row:                10,
+	}
+	c := NewCompletionManager(func(d Document) []Suggest {
+		return FilterFuzzyRanked([]Suggest{{Text: "xdeploy"}}, d.GetWordBeforeCursor(), true)
+	}, 6)
+	buf := NewBuffer()
+	buf.InsertText("dp", false, true)
+	c.Update(*buf.Document())
+
+	r.renderCompletion(buf, c)
+	// "d" and "p" are highlighted in bold and underlined.
+	expected := " x\x1b[1;4;39;49md\x1b[0;39;49me\x1b[1;4;39;49mp\x1b[0;39;49mloy"
+	if actual := string(out.buffer); !strings.Contains(actual, expected) {
+		t.Errorf("Should contain %q, but got %q", expected, actual)
+	}
+}
+
+func TestRenderGridTooSmall(t *testing.T) {
+	for _, layout := range []CompletionLayout{ListLayout, GridLayout} {
+		out := &PosixWriter{fd: syscall.Stdin}
+		r := &Render{
+			prefix:             "> ",
+			out:                out,
+			livePrefixCallback: func() (string, bool) { return "", false },
+			col:                40,
+			row:                8,
+		}
+		c := NewCompletionManager(func(Document) []Suggest { return nil }, 6)
+		c.layout = layout
+
+		r.Render(NewBuffer(), "", c, NewLexer())
+		// The grid layout needs one more row for the footer.
+		tooSmall := strings.Contains(string(out.buffer), "too small")
+		if tooSmall != (layout == GridLayout) {
+			t.Errorf("%s: Should be %v, but got %v", layout, layout == GridLayout, tooSmall)
+		}
+	}
+}
@@ -11,7 +11,7 @@ import (
 	"github.com/c-bata/go-prompt/internal/debug"
 )
 
-func (p *Prompt) handleSignals(exitCh chan int, winSizeCh chan *WinSize, stop chan struct{}) {
+func (p *Prompt) handleSignals(exitCh chan int, winSizeCh chan *WinSize, suspendCh chan struct{}, stop chan struct{}) {
 	in := p.in
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(
@@ -20,12 +20,16 @@ func (p *Prompt) handleSignals(exitCh chan int, winSizeCh chan *WinSize, stop ch
 		syscall.SIGTERM,
 		syscall.SIGQUIT,
 		syscall.SIGWINCH,
+		syscall.SIGTSTP,
 	)
 
 	for {
 		select {
 		case <-stop:
 			debug.Log("stop handleSignals")
+			// Restore the default action so that Ctrl+z stops the whole job
+			// while the executor (or a child process) owns the terminal.
+			signal.Reset(syscall.SIGTSTP)
 			return
 		case s := <-sigCh:
 			switch s {
@@ -44,7 +48,24 @@ func (p *Prompt) handleSignals(exitCh chan int, winSizeCh chan *WinSize, stop ch
 			case syscall.SIGWINCH:
 				debug.Log("Catch SIGWINCH")
 				winSizeCh <- in.GetWinSize()
+
+			case syscall.SIGTSTP: // kill -SIGTSTP XXXX
+				debug.Log("Catch SIGTSTP")
+				suspendCh <- struct{}{}
 			}
 		}
 	}
 }
+
+// suspendProcess stops the process group like the terminal driver does on Ctrl+z,
+// and blocks until the process is continued by SIGCONT (e.g. `fg` in a shell).
+func suspendProcess() {
+	contCh := make(chan os.Signal, 1)
+	signal.Notify(contCh, syscall.SIGCONT)
+	defer signal.Stop(contCh)
+
+	signal.Reset(syscall.SIGTSTP)
+	debug.AssertNoError(syscall.Kill(0, syscall.SIGTSTP))
+	<-contCh
+	debug.Log("Catch SIGCONT")
+}
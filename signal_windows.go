@@ -11,7 +11,7 @@ import (
 	"github.com/c-bata/go-prompt/internal/debug"
 )
 
-func (p *Prompt) handleSignals(exitCh chan int, winSizeCh chan *WinSize, stop chan struct{}) {
+func (p *Prompt) handleSignals(exitCh chan int, winSizeCh chan *WinSize, suspendCh chan struct{}, stop chan struct{}) {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(
 		sigCh,
@@ -43,3 +43,6 @@ func (p *Prompt) handleSignals(exitCh chan int, winSizeCh chan *WinSize, stop ch
 		}
 	}
 }
+
+// suspendProcess does nothing because there is no job control on Windows.
+func suspendProcess() {}